@@ -0,0 +1,106 @@
+package use
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArray(t *testing.T) {
+	type Address struct {
+		City   string  `usefrom:"" usein:""`
+		Street *string `usefrom:"" usein:""`
+	}
+
+	type T struct {
+		Hash      [4]byte     `usefrom:"" usein:""`
+		Addresses [2]Address  `usefrom:"" usein:""`
+		Refs      [2]*Address `usefrom:"" usein:""`
+	}
+
+	type Src struct {
+		Hash      [4]byte     `usein:""`
+		Addresses [2]Address  `usein:""`
+		Refs      [2]*Address `usein:""`
+	}
+
+	src := Src{
+		Hash:      [4]byte{1, 2, 3, 4},
+		Addresses: [2]Address{{City: "new city 0"}, {City: "new city 1", Street: asRef("new street 1")}},
+		Refs:      [2]*Address{nil, {City: "new ref city 1"}},
+	}
+
+	newObj := func() T {
+		return T{
+			Addresses: [2]Address{{City: "old city 0", Street: asRef("old street 0")}},
+			Refs:      [2]*Address{{City: "old ref city 0"}},
+		}
+	}
+
+	expected := T{
+		Hash:      [4]byte{1, 2, 3, 4},
+		Addresses: [2]Address{{City: "new city 0", Street: asRef("old street 0")}, {City: "new city 1", Street: asRef("new street 1")}},
+		Refs:      [2]*Address{{City: "old ref city 0"}, {City: "new ref city 1"}},
+	}
+	expectedSetFields := []string{"Addresses[0].City", "Addresses[1].City", "Addresses[1].Street", "Hash", "Refs[1].City"}
+
+	for name, fn := range map[string]func(dest, src any, opts ...Option) ([]string, error){
+		"from": From,
+		"in":   In,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dest := newObj()
+			setFields, err := fn(&dest, &src)
+			require.NoError(t, err)
+			require.Equal(t, expected, dest)
+			sort.Strings(setFields)
+			require.Equal(t, expectedSetFields, setFields)
+		})
+	}
+
+	t.Run("mask", func(t *testing.T) {
+		dest := newObj()
+		setFields, err := From(&dest, &src, Mask("Addresses[1].City", "Refs"))
+		require.NoError(t, err)
+		sort.Strings(setFields)
+		require.Equal(t, []string{"Addresses[1].City", "Refs[1].City"}, setFields)
+
+		_, err = From(&dest, &src, Mask("Addresses[2]"))
+		require.Error(t, err)
+	})
+
+	t.Run("track", func(t *testing.T) {
+		dest := newObj()
+		tr := Track(&dest)
+		_, err := tr.From(&src)
+		require.NoError(t, err)
+		require.Contains(t, tr.Dirty(), Change{Path: "Addresses[1].City", Old: "", New: "new city 1"})
+	})
+}
+
+func TestArrayConversion(t *testing.T) {
+	type Dest struct {
+		Vec [3]int `usefrom:""`
+	}
+	type Src struct {
+		Vec [3]string
+	}
+
+	m := NewMapper(Config{})
+
+	dest := Dest{}
+	_, err := m.From(&dest, &Src{Vec: [3]string{"1", "2", "3"}})
+	require.Error(t, err)
+
+	require.NoError(t, m.RegisterConverter(strconv.Atoi))
+
+	setFields, err := m.From(&dest, &Src{Vec: [3]string{"1", "2", "3"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Vec"}, setFields)
+	require.Equal(t, [3]int{1, 2, 3}, dest.Vec)
+
+	_, err = m.From(&dest, &Src{Vec: [3]string{"1", "x", "3"}})
+	require.ErrorContains(t, err, "element 1")
+}
@@ -0,0 +1,282 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Auto copies values from src to dest without the need of tags. Every exported field
+// of dest is filled from the source field with the same name (if it exists).
+// Nested structs are copied recursively with the same rules.
+//
+// Tags still take precedence over name matching:
+//
+//	type Dest struct {
+//		ID     int    `usefrom:"-"`           // never copied
+//		Name   string `usefrom:"FullName"`    // renamed, behaves like in From
+//		Note   string `usefrom:",nooverwrite"`
+//		Status string                         // matched by name
+//	}
+//
+//	type Src struct {
+//		Secret string `usein:"-"`      // never used as a source
+//		State  string `usein:"Status"` // explicit mapping to Dest.Status
+//	}
+//
+// The name matching can be relaxed with MatchCaseInsensitive, MatchSnakeCase
+// and MatchTrimPrefix options. Use AutoPlan to see which fields are matched.
+func Auto(dest, src any, opts ...Option) (setFields []string, err error) {
+	return defaultMapper.Auto(dest, src, opts...)
+}
+
+// AutoPlan returns the plan of fields Auto would copy between dest and src.
+// Only the types of dest and src are used, they can be nil pointers.
+func AutoPlan(dest, src any, opts ...Option) (*Plan, error) {
+	return defaultMapper.AutoPlan(dest, src, opts...)
+}
+
+func (m *Mapper) autoPlan(dest, src any, opts []Option) (*Plan, error) {
+	destT, err := structType(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	srcT, err := structType(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+
+	pl := &Plan{}
+	if err := m.explain(pl, autoMode, destT, srcT, m.newRun(autoMode, opts).opts, "", "", map[[2]reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+// MatchCaseInsensitive makes Auto match field names regardless of case (UserID ~ UserId).
+func MatchCaseInsensitive() Option {
+	return func(o *options) {
+		o.naming.caseInsensitive = true
+	}
+}
+
+// MatchSnakeCase makes Auto match snake_case and CamelCase spellings
+// of the same name (UserID ~ User_ID ~ User_Id). It implies MatchCaseInsensitive.
+func MatchSnakeCase() Option {
+	return func(o *options) {
+		o.naming.snakeCase = true
+	}
+}
+
+// MatchTrimPrefix makes Auto ignore the given prefixes of field names
+// on both sides (with prefix "Inp", InpName ~ Name).
+func MatchTrimPrefix(prefixes ...string) Option {
+	return func(o *options) {
+		o.naming.prefixes = append(o.naming.prefixes, prefixes...)
+	}
+}
+
+type naming struct {
+	caseInsensitive bool
+	snakeCase       bool
+	prefixes        []string
+}
+
+// key identifies the naming strategy in the plan cache.
+func (n naming) key() string {
+	return fmt.Sprintf("%t|%t|%s", n.caseInsensitive, n.snakeCase, strings.Join(n.prefixes, ","))
+}
+
+// normalize returns the name used for matching.
+func (n naming) normalize(name string) string {
+	for _, prefix := range n.prefixes {
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+
+	switch {
+	case n.snakeCase:
+		return toSnakeCase(name)
+	case n.caseInsensitive:
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+}
+
+// toSnakeCase converts CamelCase (and Camel_Case) name to lowercase snake_case.
+// Acronyms are kept together: HTTPServerID -> http_server_id.
+func toSnakeCase(name string) string {
+	rs := []rune(name)
+	var sb strings.Builder
+	for i, r := range rs {
+		if r == '_' {
+			continue
+		}
+		if sb.Len() > 0 && isWordStart(rs, i) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+func isWordStart(rs []rune, i int) bool {
+	prev := rs[i-1]
+	if prev == '_' {
+		return true
+	}
+	if !unicode.IsUpper(rs[i]) {
+		return false
+	}
+	nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+	return unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower)
+}
+
+// compileAuto builds the rules by matching field names. Tags override the matching:
+// `usefrom` on destination and `usein` on source define explicit rules, "-" excludes the field.
+func (m *Mapper) compileAuto(destT, srcT reflect.Type, n naming) (*plan, error) {
+	explicit := map[string]*rule{} // key is destination field name
+	candidates := map[string][]string{}
+	for i := 0; i < srcT.NumField(); i++ {
+		sf := srcT.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tg := parseTag(sf, m.inTag, m.defaults)
+		if tg == nil {
+			key := n.normalize(sf.Name)
+			candidates[key] = append(candidates[key], sf.Name)
+			continue
+		}
+		if tg.skip {
+			continue
+		}
+		if prev, ok := explicit[tg.fieldName]; ok {
+			return nil, fmt.Errorf("source fields %q and %q map to the same destination field %q", prev.srcName, sf.Name, tg.fieldName)
+		}
+		explicit[tg.fieldName] = &rule{destName: tg.fieldName, srcName: sf.Name, tag: tg}
+	}
+
+	p := &plan{}
+	for i := 0; i < destT.NumField(); i++ {
+		sf := destT.Field(i)
+		tg := parseTag(sf, m.fromTag, m.defaults)
+		er, hasExplicit := explicit[sf.Name]
+		delete(explicit, sf.Name)
+
+		if tg != nil {
+			if tg.skip {
+				continue
+			}
+			r, err := m.fromRule(sf, srcT, tg)
+			if err != nil {
+				return nil, err
+			}
+			if r != nil {
+				p.rules = append(p.rules, r)
+			}
+			continue
+		}
+
+		if hasExplicit {
+			if !sf.IsExported() {
+				return nil, fmt.Errorf("field %q is not settable", sf.Name)
+			}
+			if err := m.checkTag(er.tag, sf); err != nil {
+				return nil, err
+			}
+			ssf, _ := srcT.FieldByName(er.srcName)
+			er.nested = m.isNested(sf.Type, ssf.Type)
+			p.rules = append(p.rules, er)
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		srcName, err := n.match(sf.Name, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if srcName == "" {
+			continue
+		}
+
+		ssf, _ := srcT.FieldByName(srcName)
+		tg = &tag{}
+		*tg = m.defaults
+		tg.fieldName = srcName
+		p.rules = append(p.rules, &rule{
+			destName: sf.Name,
+			srcName:  srcName,
+			nested:   m.isNested(sf.Type, ssf.Type),
+			auto:     true,
+			tag:      tg,
+		})
+	}
+
+	// usein tags pointing to fields which are not on destination
+	for destName, er := range explicit {
+		if er.tag.omitMissing {
+			continue
+		}
+		if _, ok := destT.FieldByName(destName); !ok {
+			return nil, fmt.Errorf("destination field %q does not exist", destName)
+		}
+	}
+
+	return p, nil
+}
+
+// match finds the source field for destination field name. The same name
+// has precedence, otherwise the normalized name has to be unique.
+func (n naming) match(destName string, candidates map[string][]string) (string, error) {
+	found := candidates[n.normalize(destName)]
+	for _, c := range found {
+		if c == destName {
+			return c, nil
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("destination field %q matches more source fields: %s", destName, strings.Join(found, ", "))
+	}
+}
+
+// hasExportedFields reports if the struct (pointer to struct or array of them) has any exported field.
+// Structs without them (like time.Time) are copied as values.
+func hasExportedFields(t reflect.Type) bool {
+	if t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	t = baseType(t)
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// structType returns the struct type of v (struct or pointer to struct).
+func structType(v any) (reflect.Type, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("nil is not a struct")
+	}
+	if _, t = derefType(t); t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+	return t, nil
+}
@@ -0,0 +1,59 @@
+package use
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleAuto(t *testing.T) {
+
+	// When we use Auto, no tags are needed, fields are matched by name.
+	// Tags can still rename, exclude or add options to some fields.
+	type Dest struct {
+		ID   int    `usefrom:"-"` // never copied
+		F1   string // matched by name
+		F2   *int   `usefrom:",nooverwrite"`
+		F3   *int   `usefrom:"F3inp"` // renamed
+		Only bool   // missing in source, left untouched
+	}
+
+	type Src struct {
+		ID    int
+		F1    *string
+		F2    int
+		F3inp int
+	}
+
+	dest := Dest{
+		ID:   1,             // will not be overwritten (excluded)
+		F1:   "original f1", // will be overwritten
+		F2:   asRef(42),     // will not be overwritten
+		F3:   nil,           // will be overwritten
+		Only: true,          // will not be overwritten, not in source
+	}
+
+	src := Src{
+		ID:    2,
+		F1:    asRef("new f1"),
+		F2:    43,
+		F3inp: 44,
+	}
+
+	setFields, err := Auto(&dest, &src)
+	require.NoError(t, err)
+
+	expectedDest := Dest{
+		ID:   1,
+		F1:   "new f1",
+		F2:   asRef(42),
+		F3:   asRef(44),
+		Only: true,
+	}
+	require.Equal(t, expectedDest, dest)
+
+	expectedSetFields := []string{"F1", "F3"}
+	sort.Strings(setFields)
+	require.Equal(t, expectedSetFields, setFields)
+}
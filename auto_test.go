@@ -0,0 +1,186 @@
+package use
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoNestedStruct(t *testing.T) {
+	type Address struct {
+		City   string
+		Street *string
+	}
+
+	type T struct {
+		Name    string
+		Address *Address
+		Created time.Time // struct without exported fields is copied as value
+	}
+
+	type TInput struct {
+		Name    *string
+		Address *Address
+		Created *time.Time
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	obj := T{Name: "old"}
+	objInput := TInput{
+		Name:    asRef("new"),
+		Address: &Address{City: "Prague"},
+		Created: &created,
+	}
+
+	setFields, err := Auto(&obj, &objInput)
+	require.NoError(t, err)
+	require.Equal(t, T{Name: "new", Address: &Address{City: "Prague"}, Created: created}, obj)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Address.City", "Created", "Name"}, setFields)
+}
+
+func TestAutoTagsOverride(t *testing.T) {
+	type T struct {
+		F1 string `usefrom:"-"`
+		F2 string `usefrom:"Other"`
+		F3 string
+		F4 string
+		F5 string `usefrom:",omitmissing"`
+	}
+
+	type TInput struct {
+		F1     string
+		Other  string
+		F3     string `usein:"-"`
+		Renamy string `usein:"F4"`
+		F4     string
+	}
+
+	obj := T{F1: "old f1", F3: "old f3"}
+	objInput := TInput{F1: "f1", Other: "other", F3: "f3", Renamy: "renamy", F4: "f4"}
+
+	setFields, err := Auto(&obj, &objInput)
+	require.NoError(t, err)
+	require.Equal(t, T{F1: "old f1", F2: "other", F3: "old f3", F4: "renamy"}, obj)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F2", "F4"}, setFields)
+}
+
+func TestAutoNaming(t *testing.T) {
+	type T struct {
+		UserID    int
+		FirstName string
+		LastName  string
+	}
+
+	t.Run("case insensitive", func(t *testing.T) {
+		type TInput struct {
+			UserId    int
+			Firstname string
+			LastName  string
+		}
+
+		obj := T{}
+		_, err := Auto(&obj, &TInput{UserId: 1, Firstname: "John", LastName: "Doe"}, MatchCaseInsensitive())
+		require.NoError(t, err)
+		require.Equal(t, T{UserID: 1, FirstName: "John", LastName: "Doe"}, obj)
+	})
+
+	t.Run("snake case", func(t *testing.T) {
+		type TInput struct {
+			User_ID    int
+			First_name string
+			Lastname   string // not matched, last_name != lastname
+		}
+
+		obj := T{}
+		_, err := Auto(&obj, &TInput{User_ID: 1, First_name: "John", Lastname: "Doe"}, MatchSnakeCase())
+		require.NoError(t, err)
+		require.Equal(t, T{UserID: 1, FirstName: "John"}, obj)
+	})
+
+	t.Run("trim prefix", func(t *testing.T) {
+		type TInput struct {
+			InpUserID    int
+			InpFirstName string
+			LastName     string
+		}
+
+		obj := T{}
+		_, err := Auto(&obj, &TInput{InpUserID: 1, InpFirstName: "John", LastName: "Doe"}, MatchTrimPrefix("Inp"))
+		require.NoError(t, err)
+		require.Equal(t, T{UserID: 1, FirstName: "John", LastName: "Doe"}, obj)
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		type TInput struct {
+			UserId int
+			Userid int
+		}
+
+		obj := T{}
+		_, err := Auto(&obj, &TInput{}, MatchCaseInsensitive())
+		require.Error(t, err)
+	})
+
+	t.Run("same name has precedence", func(t *testing.T) {
+		type TInput struct {
+			UserID int
+			UserId int
+		}
+
+		obj := T{}
+		_, err := Auto(&obj, &TInput{UserID: 1, UserId: 2}, MatchCaseInsensitive())
+		require.NoError(t, err)
+		require.Equal(t, T{UserID: 1}, obj)
+	})
+}
+
+func TestAutoPlan(t *testing.T) {
+	type Address struct {
+		City string
+	}
+
+	type T struct {
+		Name    string
+		Email   string `usefrom:"Mail"`
+		Address Address
+		Skipped string `usefrom:"-"`
+	}
+
+	type TInput struct {
+		Name    *string
+		Mail    *string
+		Address *Address
+		Skipped *string
+	}
+
+	pl, err := AutoPlan((*T)(nil), TInput{})
+	require.NoError(t, err)
+	require.Equal(t, []PlanField{
+		{Dest: "Name", Src: "Name", Auto: true},
+		{Dest: "Email", Src: "Mail", Auto: false},
+		{Dest: "Address", Src: "Address", Auto: true},
+		{Dest: "Address.City", Src: "Address.City", Auto: true},
+	}, pl.Fields)
+
+	_, err = AutoPlan(42, TInput{})
+	require.Error(t, err)
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for in, out := range map[string]string{
+		"Name":         "name",
+		"UserID":       "user_id",
+		"User_Id":      "user_id",
+		"HTTPServerID": "http_server_id",
+		"Address2City": "address2_city",
+		"first_name":   "first_name",
+	} {
+		require.Equal(t, out, toSnakeCase(in), in)
+	}
+}
@@ -0,0 +1,166 @@
+package use
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoMatch is the error of FromEachByKey result, when there is no destination with the source key.
+var ErrNoMatch = errors.New("no destination with the key")
+
+// Result is the result of one item of a batch copy.
+type Result struct {
+	SetFields []string
+	Err       error
+}
+
+// Parallel makes the batch copies (FromEach, FromEachByKey) use up to n workers.
+// The items are independent, a Tracer (if used) must be safe for concurrent use.
+// n <= 1 copies the items sequentially (default). Single copy calls ignore it.
+func Parallel(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// FromEach copies srcs[i] to dests[i] as From(dests[i], srcs[i], opts...) for every index.
+// The results are per index, a failed item does not stop the others. It is an error
+// when the slices have different lengths. WithUndo and Defaults are not supported.
+//
+// Example:
+//
+//	results, err := use.FromEach(entities, rows, use.Parallel(8))
+//	for i, res := range results {
+//		if res.Err != nil {
+//			log.Printf("row %d: %v", i, res.Err)
+//		}
+//	}
+func FromEach[D, S any](dests []*D, srcs []*S, opts ...Option) ([]Result, error) {
+	if len(dests) != len(srcs) {
+		return nil, fmt.Errorf("dests and srcs have different lengths: %d, %d", len(dests), len(srcs))
+	}
+	return defaultMapper.each(fromMode, len(srcs), nil, func(i int) (any, any, error) {
+		return dests[i], srcs[i], nil
+	}, opts)
+}
+
+// FromEachByKey copies every source to the destination with the same key as From(dest, src, opts...).
+// The results are per source index, the result of a source without destination has ErrNoMatch.
+// It is an error when more destinations have the same key. More sources with the same key
+// are copied to their destination in the source order (by one worker with Parallel).
+//
+// Example:
+//
+//	results, err := use.FromEachByKey(users, rows,
+//		func(u *User) int { return u.ID },
+//		func(r *UserRow) int { return r.ID },
+//	)
+func FromEachByKey[D, S any, K comparable](dests []*D, srcs []*S, destKey func(*D) K, srcKey func(*S) K, opts ...Option) ([]Result, error) {
+	byKey := make(map[K]*D, len(dests))
+	for _, d := range dests {
+		if d == nil {
+			return nil, errors.New("dests must not contain nil")
+		}
+		k := destKey(d)
+		if _, ok := byKey[k]; ok {
+			return nil, fmt.Errorf("more destinations with the key %v", k)
+		}
+		byKey[k] = d
+	}
+
+	matched := make([]*D, len(srcs))
+	groups := make([][]int, 0, len(srcs))
+	groupOf := map[*D]int{}
+	for i, s := range srcs {
+		if s != nil {
+			matched[i] = byKey[srcKey(s)]
+		}
+		if matched[i] == nil {
+			groups = append(groups, []int{i})
+			continue
+		}
+		if g, ok := groupOf[matched[i]]; ok {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		groupOf[matched[i]] = len(groups)
+		groups = append(groups, []int{i})
+	}
+
+	return defaultMapper.each(fromMode, len(srcs), groups, func(i int) (any, any, error) {
+		if srcs[i] == nil {
+			return nil, nil, errors.New("source must not be nil")
+		}
+		if matched[i] == nil {
+			return nil, nil, ErrNoMatch
+		}
+		return matched[i], srcs[i], nil
+	}, opts)
+}
+
+// each copies n items given by item function. The options are merged once and shared by all items.
+// The items of a group (indexes in order) are copied in order by one worker, nil groups
+// make every item a group of its own.
+func (m *Mapper) each(md mode, n int, groups [][]int, item func(i int) (dest, src any, err error), opts []Option) ([]Result, error) {
+	o := m.newRun(md, opts).opts
+	if o.undo != nil {
+		return nil, errors.New("WithUndo is not supported by batch copy")
+	}
+	if o.defaults != nil {
+		return nil, errors.New("Defaults is not supported by batch copy")
+	}
+
+	results := make([]Result, n)
+	apply := func(i int) {
+		dest, src, err := item(i)
+		if err != nil {
+			results[i].Err = err
+			return
+		}
+		r := &run{m: m, mode: md, opts: o}
+		results[i].SetFields, results[i].Err = r.apply(dest, src)
+	}
+
+	if groups == nil {
+		groups = make([][]int, n)
+		for i := range groups {
+			groups[i] = []int{i}
+		}
+	}
+	applyGroup := func(g int) {
+		for _, i := range groups[g] {
+			apply(i)
+		}
+	}
+
+	workers := o.workers
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+	if workers <= 1 {
+		for g := range groups {
+			applyGroup(g)
+		}
+		return results, nil
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for g := range next {
+				applyGroup(g)
+			}
+		}()
+	}
+	for g := range groups {
+		next <- g
+	}
+	close(next)
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,134 @@
+package use
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEach(t *testing.T) {
+	type Entity struct {
+		ID    int     `usefrom:",nooverwrite"`
+		Name  string  `usefrom:""`
+		Email *string `usefrom:""`
+		Limit int     `usefrom:",default=10"`
+	}
+
+	type Row struct {
+		ID    int
+		Name  *string
+		Email *string
+		Limit *int
+	}
+
+	const n = 50
+	newDests := func() []*Entity {
+		dests := make([]*Entity, n)
+		for i := range dests {
+			dests[i] = &Entity{ID: i}
+		}
+		return dests
+	}
+	srcs := make([]*Row, n)
+	for i := range srcs {
+		srcs[i] = &Row{ID: i, Email: asRef(fmt.Sprintf("e%d@example.com", i))}
+		if i%3 == 0 {
+			srcs[i].Name = asRef(fmt.Sprintf("name %d", i))
+		}
+		if i%7 == 0 {
+			srcs[i].Limit = asRef(i)
+		}
+	}
+	srcs[5] = nil
+
+	// expected results are from single From calls
+	expectedDests := newDests()
+	expected := make([]Result, n)
+	for i := range srcs {
+		expected[i].SetFields, expected[i].Err = From(expectedDests[i], srcs[i])
+	}
+	require.Error(t, expected[5].Err)
+
+	for name, opts := range map[string][]Option{
+		"sequential": nil,
+		"parallel":   {Parallel(4)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dests := newDests()
+			results, err := FromEach(dests, srcs, opts...)
+			require.NoError(t, err)
+			require.Equal(t, expected, results)
+			require.Equal(t, expectedDests, dests)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := FromEach(newDests()[:2], srcs)
+		require.Error(t, err)
+
+		var undo Undo
+		_, err = FromEach(newDests(), srcs, WithUndo(&undo))
+		require.Error(t, err)
+
+		var defaults []string
+		_, err = FromEach(newDests(), srcs, Defaults(&defaults))
+		require.Error(t, err)
+	})
+}
+
+func TestFromEachByKey(t *testing.T) {
+	type Entity struct {
+		ID   int    `usefrom:"-"`
+		Name string `usefrom:""`
+	}
+
+	type Row struct {
+		ID   int
+		Name *string
+	}
+
+	dests := []*Entity{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}, {ID: 3, Name: "three"}}
+	srcs := []*Row{{ID: 3, Name: asRef("new three")}, {ID: 4, Name: asRef("four")}, {ID: 1}}
+
+	results, err := FromEachByKey(dests, srcs,
+		func(e *Entity) int { return e.ID },
+		func(r *Row) int { return r.ID },
+		Parallel(2),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []Result{
+		{SetFields: []string{"Name"}},
+		{Err: ErrNoMatch},
+		{},
+	}, results)
+	require.Equal(t, []*Entity{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}, {ID: 3, Name: "new three"}}, dests)
+
+	t.Run("same source keys", func(t *testing.T) {
+		dests := []*Entity{{ID: 1}, {ID: 2}}
+		var srcs []*Row
+		for i := 0; i < 100; i++ {
+			srcs = append(srcs, &Row{ID: i%2 + 1, Name: asRef(strconv.Itoa(i))})
+		}
+
+		results, err := FromEachByKey(dests, srcs,
+			func(e *Entity) int { return e.ID },
+			func(r *Row) int { return r.ID },
+			Parallel(8),
+		)
+		require.NoError(t, err)
+		for _, res := range results {
+			require.Equal(t, Result{SetFields: []string{"Name"}}, res)
+		}
+		require.Equal(t, []*Entity{{ID: 1, Name: "98"}, {ID: 2, Name: "99"}}, dests) // the last sources
+	})
+
+	_, err = FromEachByKey(append(dests, &Entity{ID: 1}), srcs,
+		func(e *Entity) int { return e.ID },
+		func(r *Row) int { return r.ID },
+	)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrNoMatch))
+}
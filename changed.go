@@ -0,0 +1,70 @@
+package use
+
+import "reflect"
+
+// OnlyChanged leaves the destination fields whose new value equals the current one untouched
+// and out of setFields (so audit logs or cache invalidation do not fire on no-op updates).
+// Values are compared by their Equal method (like time.Time), when the type has one,
+// otherwise deeply. Pointers are compared by the values they point to.
+func OnlyChanged() Option {
+	return func(o *options) {
+		o.onlyChanged = true
+	}
+}
+
+// setField sets the field of destObj with the options of the run.
+// unchanged is true when the value was not set, as it equals the current one (OnlyChanged).
+func (r *run) setField(destObj *obj, fname string, v reflect.Value, tg *tag) (wasSet, unchanged bool, err error) {
+	fv, ok := destObj.field(fname)
+	if !r.opts.onlyChanged || !ok || !fv.CanSet() || isNil(v) {
+		wasSet, err = destObj.setField(fname, v, tg, &r.m.conv, &r.m.tr)
+		return wasSet, false, err
+	}
+
+	// the new value is prepared aside to be compared with the current one
+	nv := reflect.New(fv.Type()).Elem()
+	nv.Set(fv)
+	if wasSet, err = setValue(nv, v, tg, &r.m.conv, &r.m.tr); !wasSet || err != nil {
+		return false, false, err
+	}
+	if valuesEqual(fv, nv) {
+		return false, true, nil
+	}
+	fv.Set(nv)
+	return true, false, nil
+}
+
+// valuesEqual compares the values by Equal method or deeply, pointers by the values they point to.
+func valuesEqual(a, b reflect.Value) bool {
+	for a.Kind() == reflect.Ptr && b.Kind() == reflect.Ptr {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	if eq, ok := equalMethod(a); ok {
+		return eq.Call([]reflect.Value{b})[0].Bool()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// equalMethod returns the method Equal(T) bool of v (of type T or *T).
+func equalMethod(v reflect.Value) (reflect.Value, bool) {
+	m := v.MethodByName("Equal")
+	if !m.IsValid() && v.CanAddr() {
+		m = v.Addr().MethodByName("Equal")
+	}
+	if !m.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	mt := m.Type()
+	if mt.NumIn() != 1 || mt.In(0) != v.Type() || mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool {
+		return reflect.Value{}, false
+	}
+	return m, true
+}
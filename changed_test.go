@@ -0,0 +1,77 @@
+package use
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnlyChanged(t *testing.T) {
+	type Address struct {
+		City string `usefrom:""`
+	}
+
+	type T struct {
+		Name    string            `usefrom:""`
+		Email   *string           `usefrom:""`
+		Tags    []string          `usefrom:""`
+		Meta    map[string]string `usefrom:""`
+		At      time.Time         `usefrom:""`
+		Limit   int               `usefrom:",default=0"`
+		Address *Address          `usefrom:""`
+	}
+
+	type Src struct {
+		Name    string
+		Email   *string
+		Tags    []string
+		Meta    map[string]string
+		At      *string
+		Limit   *int
+		Address *Address
+	}
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	newObj := func() T {
+		return T{
+			Name:    "name",
+			Email:   asRef("email"),
+			Tags:    []string{"a", "b"},
+			Meta:    map[string]string{"k": "v"},
+			At:      at.In(time.FixedZone("CEST", 2*60*60)), // the same instant, different location
+			Address: &Address{City: "city"},
+		}
+	}
+
+	src := Src{
+		Name:    "name",
+		Email:   asRef("email"),
+		Tags:    []string{"a", "b", "c"},
+		Meta:    map[string]string{"k": "v"},
+		At:      asRef("2024-05-01T10:00:00Z"),
+		Address: &Address{City: "new city"},
+	}
+
+	dest := newObj()
+	email := dest.Email
+	var events []Event
+	setFields, err := From(&dest, &src, OnlyChanged(), WithTracer(TracerFunc(func(e Event) {
+		events = append(events, e)
+	})))
+	require.NoError(t, err)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Address.City", "Tags"}, setFields)
+	require.Same(t, email, dest.Email, "pointer compared by value is not replaced")
+	require.Contains(t, events, Event{Path: "Name", Reason: ReasonUnchanged})
+	require.Contains(t, events, Event{Path: "Limit", Reason: ReasonUnchanged})
+
+	t.Run("without option", func(t *testing.T) {
+		dest := newObj()
+		setFields, err := From(&dest, &src)
+		require.NoError(t, err)
+		sort.Strings(setFields)
+		require.Equal(t, []string{"Address.City", "At", "Email", "Limit", "Meta", "Name", "Tags"}, setFields)
+	})
+}
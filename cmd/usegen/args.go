@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type mode string
+
+const (
+	fromMode mode = "From"
+	inMode   mode = "In"
+)
+
+// pair is a single function to generate.
+type pair struct {
+	mode     mode
+	dest     string
+	src      string
+	funcName string
+}
+
+type config struct {
+	dir    string
+	output string
+	pairs  []pair
+}
+
+// pairsFlag collects repeated -from/-in flags.
+type pairsFlag struct {
+	mode  mode
+	pairs *[]pair
+}
+
+func (f pairsFlag) String() string {
+	return ""
+}
+
+func (f pairsFlag) Set(v string) error {
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid value %q, expected Dest:Src[:FuncName]", v)
+	}
+
+	p := pair{mode: f.mode, dest: parts[0], src: parts[1]}
+	switch {
+	case len(parts) == 3 && parts[2] != "":
+		p.funcName = parts[2]
+	case f.mode == fromMode:
+		p.funcName = p.dest + "From" + p.src
+	default:
+		p.funcName = p.src + "Into" + p.dest
+	}
+
+	*f.pairs = append(*f.pairs, p)
+	return nil
+}
+
+func parseArgs(args []string) (*config, error) {
+	cfg := &config{}
+	fs := flag.NewFlagSet("usegen", flag.ContinueOnError)
+	fs.StringVar(&cfg.dir, "dir", ".", "package directory")
+	fs.StringVar(&cfg.output, "o", "use_gen.go", "output file name")
+	fs.Var(pairsFlag{mode: fromMode, pairs: &cfg.pairs}, "from", "Dest:Src[:FuncName] with semantics of use.From")
+	fs.Var(pairsFlag{mode: inMode, pairs: &cfg.pairs}, "in", "Dest:Src[:FuncName] with semantics of use.In")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if len(cfg.pairs) == 0 {
+		return nil, errors.New("nothing to generate, use -from or -in")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,613 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	fromTag = "usefrom"
+	inTag   = "usein"
+)
+
+// rule is a single field copy, the same as the rule compiled by package use.
+type rule struct {
+	destName string
+	destT    types.Type
+	srcName  string
+	srcT     types.Type
+	nested   bool
+	tag      *tag
+}
+
+type tag struct {
+	fieldName   string
+	noOverwrite bool
+	omitMissing bool
+	skip        bool
+}
+
+// helper is a generated function copying one pair of struct types.
+type helper struct {
+	name  string
+	mode  mode
+	destT types.Type
+	srcT  types.Type
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string // path -> name
+	helpers map[string]*helper
+	names   map[string]bool
+	queue   []*helper
+	body    bytes.Buffer
+}
+
+func generate(cfg *config) ([]byte, error) {
+	pkg, err := loadPackage(cfg.dir, cfg.output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		imports: map[string]string{"errors": "errors"},
+		helpers: map[string]*helper{},
+		names:   map[string]bool{},
+	}
+
+	for _, p := range cfg.pairs {
+		if err := g.genPair(p); err != nil {
+			return nil, fmt.Errorf("%s %s:%s: %w", strings.ToLower(string(p.mode)), p.dest, p.src, err)
+		}
+	}
+
+	for len(g.queue) > 0 {
+		h := g.queue[0]
+		g.queue = g.queue[1:]
+		if err := g.genHelper(h); err != nil {
+			return nil, err
+		}
+	}
+
+	g.genPathHelper()
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by usegen; DO NOT EDIT.\n\npackage %s\n\n", pkg.Name())
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	out.WriteString("import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// loadPackage parses and type checks the package in dir, files excluded by build constraints
+// are skipped. The output file is skipped too, so the stale generated code does not influence the result.
+func loadPackage(dir, output string) (*types.Package, error) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	imports := map[string]bool{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		// files excluded by build constraints (like `//go:build ignore` tools) are not part of the package
+		match, err := build.Default.MatchFile(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			imports[path] = true
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no go files in %q", dir)
+	}
+
+	lookup, err := exportLookup(dir, imports)
+	if err != nil {
+		return nil, err
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "gc", lookup)}
+	return conf.Check(files[0].Name.Name, fset, files, nil)
+}
+
+// exportLookup returns the lookup of the export data of the imported packages and their
+// dependencies, built by the go tool in dir, so the imports are resolved in the module of the package.
+func exportLookup(dir string, imports map[string]bool) (importer.Lookup, error) {
+	exports := map[string]string{}
+	if len(imports) > 0 {
+		args := []string{"list", "-export", "-deps", "-f", "{{if .Export}}{{.ImportPath}}={{.Export}}{{end}}"}
+		for path := range imports {
+			if path != "C" {
+				args = append(args, path)
+			}
+		}
+
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("listing imported packages: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if path, file, ok := strings.Cut(line, "="); ok {
+				exports[path] = file
+			}
+		}
+	}
+
+	return func(path string) (io.ReadCloser, error) {
+		file, ok := exports[path]
+		if !ok {
+			return nil, fmt.Errorf("no export data of package %q", path)
+		}
+		return os.Open(file)
+	}, nil
+}
+
+func (g *generator) genPair(p pair) error {
+	destT, err := g.lookupStruct(p.dest)
+	if err != nil {
+		return err
+	}
+	srcT, err := g.lookupStruct(p.src)
+	if err != nil {
+		return err
+	}
+
+	h := g.helper(p.mode, destT, srcT)
+
+	fmt.Fprintf(&g.body, "// %s copies values from src to dest with the semantics of use.%s(dest, src).\n", p.funcName, p.mode)
+	fmt.Fprintf(&g.body, "func %s(dest *%s, src *%s) ([]string, error) {\n", p.funcName, g.typeString(destT), g.typeString(srcT))
+	g.body.WriteString("if dest == nil || src == nil {\n")
+	g.body.WriteString("return nil, errors.New(\"dest and src must be non nil references to struct\")\n")
+	g.body.WriteString("}\n")
+	fmt.Fprintf(&g.body, "return %s(dest, src, \"\", nil), nil\n", h.name)
+	g.body.WriteString("}\n\n")
+	return nil
+}
+
+func (g *generator) lookupStruct(name string) (types.Type, error) {
+	obj := g.pkg.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("type %q not found", name)
+	}
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a type", name)
+	}
+	if _, ok := tn.Type().Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("%q is not a struct", name)
+	}
+	return tn.Type(), nil
+}
+
+// helper returns the (possibly queued) helper function for the pair of struct types.
+func (g *generator) helper(md mode, destT, srcT types.Type) *helper {
+	key := string(md) + "|" + types.TypeString(destT, nil) + "|" + types.TypeString(srcT, nil)
+	if h, ok := g.helpers[key]; ok {
+		return h
+	}
+
+	base := "usegen" + string(md) + g.typeName(destT) + g.typeName(srcT)
+	name := base
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[name] = true
+
+	h := &helper{name: name, mode: md, destT: destT, srcT: srcT}
+	g.helpers[key] = h
+	g.queue = append(g.queue, h)
+	return h
+}
+
+func (g *generator) genHelper(h *helper) error {
+	var rules []*rule
+	var err error
+	if h.mode == fromMode {
+		rules, err = g.fromRules(h.destT, h.srcT)
+	} else {
+		rules, err = g.inRules(h.destT, h.srcT)
+	}
+	if err != nil {
+		return fmt.Errorf("%s -> %s: %w", types.TypeString(h.srcT, nil), types.TypeString(h.destT, nil), err)
+	}
+
+	fmt.Fprintf(&g.body, "func %s(dest *%s, src *%s, path string, setFields []string) []string {\n", h.name, g.typeString(h.destT), g.typeString(h.srcT))
+	for _, r := range rules {
+		var err error
+		if r.nested {
+			err = g.genNested(h.mode, r)
+		} else {
+			err = g.genValue(r)
+		}
+		if err != nil {
+			return fmt.Errorf("%s -> %s field %q: %w", types.TypeString(h.srcT, nil), types.TypeString(h.destT, nil), r.destName, err)
+		}
+	}
+	g.body.WriteString("return setFields\n}\n\n")
+	return nil
+}
+
+// genValue generates copy of a field as a value (the same as setField).
+func (g *generator) genValue(r *rule) error {
+	// a value that cannot be nil is never overwritten
+	if r.tag.noOverwrite && !isNilable(r.destT) {
+		return nil
+	}
+
+	var assign string
+	switch {
+	case types.Identical(r.destT, r.srcT):
+		assign = "dest.%s = src.%s"
+	case isPointer(r.destT) && types.Identical(elem(r.destT), r.srcT):
+		assign = "dest.%s = &src.%s"
+	case isPointer(r.srcT) && types.Identical(elem(r.srcT), r.destT):
+		assign = "dest.%s = *src.%s"
+	default:
+		return fmt.Errorf("types not assignable. dest %s, src %s", g.typeString(r.destT), g.typeString(r.srcT))
+	}
+
+	var conds []string
+	if isNilable(r.srcT) {
+		conds = append(conds, fmt.Sprintf("src.%s != nil", r.srcName))
+	}
+	if r.tag.noOverwrite {
+		conds = append(conds, fmt.Sprintf("dest.%s == nil", r.destName))
+	}
+
+	fmt.Fprintf(&g.body, "// %s <- %s\n", r.destName, r.srcName)
+	if len(conds) > 0 {
+		fmt.Fprintf(&g.body, "if %s {\n", strings.Join(conds, " && "))
+	}
+	fmt.Fprintf(&g.body, assign+"\n", r.destName, r.srcName)
+	fmt.Fprintf(&g.body, "setFields = append(setFields, usegenPath(path, %q))\n", r.destName)
+	if len(conds) > 0 {
+		g.body.WriteString("}\n")
+	}
+	return nil
+}
+
+// genNested generates recursive copy of nested structs (allocating nil destination).
+func (g *generator) genNested(md mode, r *rule) error {
+	if !isStructOrPtrToStruct(r.srcT) {
+		return fmt.Errorf("source field %q is not a struct", r.srcName)
+	}
+	// non nil struct value is never overwritten
+	if r.tag.noOverwrite && !isPointer(r.destT) {
+		return nil
+	}
+
+	h := g.helper(md, deref(r.destT), deref(r.srcT))
+
+	var conds []string
+	if isPointer(r.srcT) {
+		conds = append(conds, fmt.Sprintf("src.%s != nil", r.srcName))
+	}
+	if r.tag.noOverwrite {
+		conds = append(conds, fmt.Sprintf("dest.%s == nil", r.destName))
+	}
+
+	destRef, srcRef := "&dest."+r.destName, "&src."+r.srcName
+	if isPointer(r.destT) {
+		destRef = "dest." + r.destName
+	}
+	if isPointer(r.srcT) {
+		srcRef = "src." + r.srcName
+	}
+
+	fmt.Fprintf(&g.body, "// %s <- %s\n", r.destName, r.srcName)
+	if len(conds) > 0 {
+		fmt.Fprintf(&g.body, "if %s {\n", strings.Join(conds, " && "))
+	}
+	if isPointer(r.destT) {
+		if !r.tag.noOverwrite {
+			fmt.Fprintf(&g.body, "if dest.%s == nil {\n", r.destName)
+		}
+		fmt.Fprintf(&g.body, "dest.%s = new(%s)\n", r.destName, g.typeString(deref(r.destT)))
+		if !r.tag.noOverwrite {
+			g.body.WriteString("}\n")
+		}
+	}
+	fmt.Fprintf(&g.body, "setFields = %s(%s, %s, usegenPath(path, %q), setFields)\n", h.name, destRef, srcRef, r.destName)
+	if len(conds) > 0 {
+		g.body.WriteString("}\n")
+	}
+	return nil
+}
+
+func (g *generator) genPathHelper() {
+	g.body.WriteString(`func usegenPath(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+`)
+}
+
+// fromRules are the rules defined by `usefrom` tags on the destination struct.
+func (g *generator) fromRules(destT, srcT types.Type) ([]*rule, error) {
+	st := destT.Underlying().(*types.Struct)
+	var rules []*rule
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tg, err := parseTag(f.Name(), st.Tag(i), fromTag)
+		if err != nil {
+			return nil, err
+		}
+		if tg == nil || tg.skip {
+			continue
+		}
+		if !f.Exported() {
+			return nil, fmt.Errorf("field %q is not settable", f.Name())
+		}
+
+		if err := checkSupported(f.Type()); err != nil {
+			return nil, err
+		}
+
+		sf := g.lookupField(srcT, tg.fieldName)
+		if sf == nil {
+			// missing sub struct in source is not an error
+			if tg.omitMissing || isStructOrPtrToStruct(f.Type()) {
+				continue
+			}
+			return nil, fmt.Errorf("invalid value of source field %q", tg.fieldName)
+		}
+		if err := checkSupported(sf.Type()); err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, &rule{
+			destName: f.Name(),
+			destT:    f.Type(),
+			srcName:  tg.fieldName,
+			srcT:     sf.Type(),
+			nested:   isNestedStruct(f.Type()),
+			tag:      tg,
+		})
+	}
+	return rules, nil
+}
+
+// inRules are the rules defined by `usein` tags on the source struct.
+func (g *generator) inRules(destT, srcT types.Type) ([]*rule, error) {
+	st := srcT.Underlying().(*types.Struct)
+	var rules []*rule
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tg, err := parseTag(f.Name(), st.Tag(i), inTag)
+		if err != nil {
+			return nil, err
+		}
+		if tg == nil || tg.skip {
+			continue
+		}
+		if !f.Exported() {
+			return nil, fmt.Errorf("source field %q is not exported", f.Name())
+		}
+
+		df := g.lookupField(destT, tg.fieldName)
+		if df == nil {
+			if tg.omitMissing {
+				continue
+			}
+			return nil, fmt.Errorf("destination field %q does not exist", tg.fieldName)
+		}
+		for _, t := range []types.Type{f.Type(), df.Type()} {
+			if err := checkSupported(t); err != nil {
+				return nil, err
+			}
+		}
+
+		rules = append(rules, &rule{
+			destName: tg.fieldName,
+			destT:    df.Type(),
+			srcName:  f.Name(),
+			srcT:     f.Type(),
+			nested:   isNestedStruct(df.Type()),
+			tag:      tg,
+		})
+	}
+	return rules, nil
+}
+
+// checkSupported returns error for field types package use handles in a special way
+// the generator does not implement.
+func checkSupported(t types.Type) error {
+	unsupported := fmt.Errorf("optional type %s is not supported by usegen", types.TypeString(t, nil))
+	if n, ok := deref(t).(*types.Named); ok {
+		obj := n.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "database/sql" && strings.HasPrefix(obj.Name(), "Null") {
+			return unsupported
+		}
+	}
+
+	if isPointer(t) && isPointer(elem(t)) {
+		return fmt.Errorf("multi-level pointer %s is not supported by usegen", types.TypeString(t, nil))
+	}
+
+	if a, ok := t.Underlying().(*types.Array); ok {
+		if _, ok := deref(a.Elem()).Underlying().(*types.Struct); ok {
+			return fmt.Errorf("array of structs %s is not supported by usegen", types.TypeString(t, nil))
+		}
+	}
+
+	mset := types.NewMethodSet(types.NewPointer(deref(t)))
+	for _, name := range []string{"UseValue", "UseSetValue"} {
+		if mset.Lookup(nil, name) != nil {
+			return unsupported
+		}
+	}
+	return nil
+}
+
+// lookupField returns exported field (including promoted ones) or nil.
+func (g *generator) lookupField(t types.Type, name string) *types.Var {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, g.pkg, name)
+	v, ok := obj.(*types.Var)
+	if !ok || !v.IsField() || !v.Exported() {
+		return nil
+	}
+	return v
+}
+
+// parseTag parses the tag the same way as package use. Unknown options are
+// an error, so the generated code never silently differs from the reflective copy.
+func parseTag(fieldName, rawTag, tagName string) (*tag, error) {
+	v, ok := reflect.StructTag(rawTag).Lookup(tagName)
+	if !ok {
+		return nil, nil
+	}
+	if v == "-" {
+		return &tag{skip: true}, nil
+	}
+
+	vparts := strings.Split(v, ",")
+	tg := &tag{fieldName: vparts[0]}
+	for _, vpart := range vparts[1:] {
+		switch vpart {
+		case "nooverwrite":
+			tg.noOverwrite = true
+		case "omitmissing":
+			tg.omitMissing = true
+		case "overwrite":
+			tg.noOverwrite = false
+		case "mustexist":
+			tg.omitMissing = false
+		default:
+			return nil, fmt.Errorf("field %q: tag option %q is not supported by usegen", fieldName, vpart)
+		}
+	}
+
+	if tg.fieldName == "" {
+		tg.fieldName = fieldName
+	}
+	return tg, nil
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	})
+}
+
+// typeName is used in helper function names.
+func (g *generator) typeName(t types.Type) string {
+	name := "Struct"
+	if n, ok := t.(*types.Named); ok {
+		name = n.Obj().Name()
+		if p := n.Obj().Pkg(); p != nil && p != g.pkg {
+			name = strings.ToUpper(p.Name()[:1]) + p.Name()[1:] + strings.ToUpper(name[:1]) + name[1:]
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
+
+func elem(t types.Type) types.Type {
+	return t.Underlying().(*types.Pointer).Elem()
+}
+
+func deref(t types.Type) types.Type {
+	if isPointer(t) {
+		return elem(t)
+	}
+	return t
+}
+
+// isNilable mirrors isNil of package use.
+func isNilable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNestedStruct reports if the struct (or pointer to struct) is copied recursively, structs
+// without exported fields (like time.Time) are copied as values (like hasExportedFields of package use).
+func isNestedStruct(t types.Type) bool {
+	st, ok := deref(t).Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Exported() {
+			return true
+		}
+	}
+	return false
+}
+
+// isStructOrPtrToStruct mirrors containsStructOrPtrToStruct of package use.
+func isStructOrPtrToStruct(t types.Type) bool {
+	_, ok := deref(t).Underlying().(*types.Struct)
+	return ok
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateExample checks the committed generated code of the example package is up to date.
+func TestGenerateExample(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	args := goGenerateArgs(t, filepath.Join(dir, "example.go"))
+
+	cfg, err := parseArgs(append(args, "-dir", dir))
+	require.NoError(t, err)
+
+	src, err := generate(cfg)
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join(dir, cfg.output))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src), "run go generate ./...")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	dir := filepath.Join("testdata", "bad")
+
+	for name, args := range map[string][]string{
+		"types not assignable":      {"-from", "DestMismatch:Src"},
+		"unsupported tag option":    {"-from", "DestOption:Src"},
+		"missing source field":      {"-from", "DestMissing:Src"},
+		"missing destination field": {"-in", "Src:SrcMissing"},
+		"sql null":                  {"-from", "DestNull:SrcNull"},
+		"optional":                  {"-from", "DestOpt:SrcOpt"},
+		"array of structs":          {"-from", "DestArr:SrcArr"},
+		"multi-level pointer":       {"-from", "DestPtrPtr:SrcPtrPtr"},
+		"unknown type":              {"-from", "DestMismatch:Nope"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseArgs(append(args, "-dir", dir))
+			require.NoError(t, err)
+
+			_, err = generate(cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestGenerateBuildConstraints(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.go"), []byte(`package example
+
+type Dest struct {
+	Name string `+"`usefrom:\"\"`"+`
+}
+
+type Src struct {
+	Name *string
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools.go"), []byte(`//go:build ignore
+
+package main
+
+func main() {}
+`), 0o600))
+
+	cfg, err := parseArgs([]string{"-from", "Dest:Src", "-dir", dir})
+	require.NoError(t, err)
+
+	_, err = generate(cfg)
+	require.NoError(t, err)
+}
+
+func TestGenerateImports(t *testing.T) {
+	cfg, err := parseArgs([]string{"-from", "Dest:Src", "-dir", filepath.Join("testdata", "imports")})
+	require.NoError(t, err)
+
+	src, err := generate(cfg)
+	require.NoError(t, err)
+	require.Contains(t, string(src), `"github.com/dacz/use/cmd/usegen/internal/example"`)
+	require.Contains(t, string(src), "*example.AddressInput")
+}
+
+func TestParseTagUnsupportedOption(t *testing.T) {
+	_, err := parseTag("F2", `usefrom:",unknownoption"`, fromTag)
+	require.Error(t, err)
+
+	tg, err := parseTag("F2", `usefrom:"Other,nooverwrite,omitmissing"`, fromTag)
+	require.NoError(t, err)
+	require.Equal(t, &tag{fieldName: "Other", noOverwrite: true, omitMissing: true}, tg)
+}
+
+func TestParseArgs(t *testing.T) {
+	cfg, err := parseArgs([]string{"-from", "Dest:Src", "-in", "Dest:Patch", "-from", "A:B:CopyAB", "-o", "gen.go"})
+	require.NoError(t, err)
+	require.Equal(t, "gen.go", cfg.output)
+	require.Equal(t, []pair{
+		{mode: fromMode, dest: "Dest", src: "Src", funcName: "DestFromSrc"},
+		{mode: inMode, dest: "Dest", src: "Patch", funcName: "PatchIntoDest"},
+		{mode: fromMode, dest: "A", src: "B", funcName: "CopyAB"},
+	}, cfg.pairs)
+
+	_, err = parseArgs(nil)
+	require.Error(t, err)
+
+	_, err = parseArgs([]string{"-from", "Dest"})
+	require.Error(t, err)
+}
+
+// goGenerateArgs returns usegen arguments of the go:generate directive in file.
+func goGenerateArgs(t *testing.T, file string) []string {
+	t.Helper()
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "//go:generate ") {
+			continue
+		}
+		_, args, ok := strings.Cut(line, "cmd/usegen")
+		require.True(t, ok, line)
+		return strings.Fields(args)
+	}
+	t.Fatalf("no go:generate directive in %s", file)
+	return nil
+}
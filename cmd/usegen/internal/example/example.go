@@ -0,0 +1,57 @@
+// Package example is used to test that the code generated by usegen
+// has the same results as the reflective use.From and use.In.
+package example
+
+import "time"
+
+//go:generate go run github.com/dacz/use/cmd/usegen -from Entity:Input -in Entity:Patch
+
+type Address struct {
+	City   string  `usefrom:"Town" usein:""`
+	Street *string `usefrom:"" usein:""`
+}
+
+type AddressInput struct {
+	Town   *string
+	Street *string
+}
+
+type Entity struct {
+	Name     string            `usefrom:""`
+	Email    *string           `usefrom:"Mail,nooverwrite"`
+	Age      int               `usefrom:",omitmissing"`
+	Nick     *string           `usefrom:""`
+	Tags     []string          `usefrom:""`
+	Meta     map[string]string `usefrom:""`
+	Created  time.Time         `usefrom:"Created,omitmissing"`
+	Address  *Address          `usefrom:""`
+	Billing  *Address          `usefrom:",nooverwrite"`
+	Shipping Address           `usefrom:"Shipping"`
+	Code     string            `usefrom:",nooverwrite"`
+	Count    int               `usefrom:",nooverwrite"`
+	Internal string
+}
+
+type Input struct {
+	Name     *string
+	Mail     *string
+	Nick     string
+	Tags     []string
+	Meta     map[string]string
+	Address  *AddressInput
+	Billing  *AddressInput
+	Shipping *AddressInput
+	Code     *string
+	Count    int
+}
+
+type Patch struct {
+	Name    *string   `usein:""`
+	Email   string    `usein:",nooverwrite"`
+	Age     *int      `usein:""`
+	Street  *string   `usein:"Nick"`
+	Created time.Time `usein:""`
+	Address *Address  `usein:",nooverwrite"`
+	Ignored string    `usein:"-"`
+	Other   string    `usein:",omitmissing"`
+}
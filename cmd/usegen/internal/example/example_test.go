@@ -0,0 +1,112 @@
+package example
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dacz/use"
+	"github.com/stretchr/testify/require"
+)
+
+func ref[T any](v T) *T {
+	return &v
+}
+
+func entities() map[string]func() Entity {
+	return map[string]func() Entity{
+		"empty": func() Entity { return Entity{} },
+		"filled": func() Entity {
+			return Entity{
+				Name:     "old name",
+				Email:    ref("old@example.com"),
+				Age:      42,
+				Nick:     ref("old nick"),
+				Tags:     []string{"old"},
+				Meta:     map[string]string{"old": "1"},
+				Created:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				Address:  &Address{City: "Old City", Street: ref("Old Street")},
+				Billing:  &Address{City: "Old Billing"},
+				Shipping: Address{City: "Old Shipping"},
+				Code:     "old code",
+				Count:    3,
+				Internal: "internal",
+			}
+		},
+	}
+}
+
+func TestGeneratedFrom(t *testing.T) {
+	inputs := map[string]Input{
+		"empty": {},
+		"full": {
+			Name:     ref("name"),
+			Mail:     ref("new@example.com"),
+			Nick:     "nick",
+			Tags:     []string{"a", "b"},
+			Meta:     map[string]string{"a": "b"},
+			Address:  &AddressInput{Town: ref("City")},
+			Billing:  &AddressInput{Town: ref("Billing"), Street: ref("Billing Street")},
+			Shipping: &AddressInput{Street: ref("Shipping Street")},
+			Code:     ref("new code"),
+			Count:    5,
+		},
+	}
+
+	for ename, newEntity := range entities() {
+		for iname, input := range inputs {
+			t.Run(ename+"/"+iname, func(t *testing.T) {
+				reflected, generated := newEntity(), newEntity()
+				srcReflected, srcGenerated := input, input
+
+				expectedFields, err := use.From(&reflected, &srcReflected)
+				require.NoError(t, err)
+				setFields, err := EntityFromInput(&generated, &srcGenerated)
+				require.NoError(t, err)
+
+				require.Equal(t, reflected, generated)
+				require.Equal(t, expectedFields, setFields)
+			})
+		}
+	}
+}
+
+func TestGeneratedIn(t *testing.T) {
+	patches := map[string]Patch{
+		"empty": {},
+		"full": {
+			Name:    ref("name"),
+			Email:   "new@example.com",
+			Age:     ref(7),
+			Street:  ref("street"),
+			Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Address: &Address{City: "City"},
+			Ignored: "ignored",
+			Other:   "other",
+		},
+	}
+
+	for ename, newEntity := range entities() {
+		for pname, patch := range patches {
+			t.Run(ename+"/"+pname, func(t *testing.T) {
+				reflected, generated := newEntity(), newEntity()
+				srcReflected, srcGenerated := patch, patch
+
+				expectedFields, err := use.In(&reflected, &srcReflected)
+				require.NoError(t, err)
+				setFields, err := PatchIntoEntity(&generated, &srcGenerated)
+				require.NoError(t, err)
+
+				require.Equal(t, reflected, generated)
+				require.Equal(t, expectedFields, setFields)
+			})
+		}
+	}
+}
+
+func TestGeneratedNil(t *testing.T) {
+	_, err := EntityFromInput(nil, &Input{})
+	require.Error(t, err)
+
+	_, err = use.From((*Entity)(nil), &Input{})
+	require.Error(t, err)
+}
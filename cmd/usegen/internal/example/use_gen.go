@@ -0,0 +1,131 @@
+// Code generated by usegen; DO NOT EDIT.
+
+package example
+
+import (
+	"errors"
+)
+
+// EntityFromInput copies values from src to dest with the semantics of use.From(dest, src).
+func EntityFromInput(dest *Entity, src *Input) ([]string, error) {
+	if dest == nil || src == nil {
+		return nil, errors.New("dest and src must be non nil references to struct")
+	}
+	return usegenFromEntityInput(dest, src, "", nil), nil
+}
+
+// PatchIntoEntity copies values from src to dest with the semantics of use.In(dest, src).
+func PatchIntoEntity(dest *Entity, src *Patch) ([]string, error) {
+	if dest == nil || src == nil {
+		return nil, errors.New("dest and src must be non nil references to struct")
+	}
+	return usegenInEntityPatch(dest, src, "", nil), nil
+}
+
+func usegenFromEntityInput(dest *Entity, src *Input, path string, setFields []string) []string {
+	// Name <- Name
+	if src.Name != nil {
+		dest.Name = *src.Name
+		setFields = append(setFields, usegenPath(path, "Name"))
+	}
+	// Email <- Mail
+	if src.Mail != nil && dest.Email == nil {
+		dest.Email = src.Mail
+		setFields = append(setFields, usegenPath(path, "Email"))
+	}
+	// Nick <- Nick
+	dest.Nick = &src.Nick
+	setFields = append(setFields, usegenPath(path, "Nick"))
+	// Tags <- Tags
+	if src.Tags != nil {
+		dest.Tags = src.Tags
+		setFields = append(setFields, usegenPath(path, "Tags"))
+	}
+	// Meta <- Meta
+	if src.Meta != nil {
+		dest.Meta = src.Meta
+		setFields = append(setFields, usegenPath(path, "Meta"))
+	}
+	// Address <- Address
+	if src.Address != nil {
+		if dest.Address == nil {
+			dest.Address = new(Address)
+		}
+		setFields = usegenFromAddressAddressInput(dest.Address, src.Address, usegenPath(path, "Address"), setFields)
+	}
+	// Billing <- Billing
+	if src.Billing != nil && dest.Billing == nil {
+		dest.Billing = new(Address)
+		setFields = usegenFromAddressAddressInput(dest.Billing, src.Billing, usegenPath(path, "Billing"), setFields)
+	}
+	// Shipping <- Shipping
+	if src.Shipping != nil {
+		setFields = usegenFromAddressAddressInput(&dest.Shipping, src.Shipping, usegenPath(path, "Shipping"), setFields)
+	}
+	return setFields
+}
+
+func usegenInEntityPatch(dest *Entity, src *Patch, path string, setFields []string) []string {
+	// Name <- Name
+	if src.Name != nil {
+		dest.Name = *src.Name
+		setFields = append(setFields, usegenPath(path, "Name"))
+	}
+	// Email <- Email
+	if dest.Email == nil {
+		dest.Email = &src.Email
+		setFields = append(setFields, usegenPath(path, "Email"))
+	}
+	// Age <- Age
+	if src.Age != nil {
+		dest.Age = *src.Age
+		setFields = append(setFields, usegenPath(path, "Age"))
+	}
+	// Nick <- Street
+	if src.Street != nil {
+		dest.Nick = src.Street
+		setFields = append(setFields, usegenPath(path, "Nick"))
+	}
+	// Created <- Created
+	dest.Created = src.Created
+	setFields = append(setFields, usegenPath(path, "Created"))
+	// Address <- Address
+	if src.Address != nil && dest.Address == nil {
+		dest.Address = new(Address)
+		setFields = usegenInAddressAddress(dest.Address, src.Address, usegenPath(path, "Address"), setFields)
+	}
+	return setFields
+}
+
+func usegenFromAddressAddressInput(dest *Address, src *AddressInput, path string, setFields []string) []string {
+	// City <- Town
+	if src.Town != nil {
+		dest.City = *src.Town
+		setFields = append(setFields, usegenPath(path, "City"))
+	}
+	// Street <- Street
+	if src.Street != nil {
+		dest.Street = src.Street
+		setFields = append(setFields, usegenPath(path, "Street"))
+	}
+	return setFields
+}
+
+func usegenInAddressAddress(dest *Address, src *Address, path string, setFields []string) []string {
+	// City <- City
+	dest.City = src.City
+	setFields = append(setFields, usegenPath(path, "City"))
+	// Street <- Street
+	if src.Street != nil {
+		dest.Street = src.Street
+		setFields = append(setFields, usegenPath(path, "Street"))
+	}
+	return setFields
+}
+
+func usegenPath(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
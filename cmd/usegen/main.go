@@ -0,0 +1,49 @@
+// Command usegen generates reflection free copy functions from `usefrom` and `usein` tags.
+//
+// The generated functions have the semantics of use.From and use.In (nil handling,
+// `nooverwrite`, `omitmissing`, allocation of nested structs and the returned setFields),
+// but without reflection. Usage with go generate:
+//
+//	//go:generate go run github.com/dacz/use/cmd/usegen -from Entity:Input -in Entity:Patch
+//
+// generates into use_gen.go
+//
+//	func EntityFromInput(dest *Entity, src *Input) ([]string, error) // as use.From(dest, src)
+//	func PatchIntoEntity(dest *Entity, src *Patch) ([]string, error) // as use.In(dest, src)
+//
+// Flags:
+//
+//	-from Dest:Src[:FuncName]  generate function with semantics of use.From (repeatable)
+//	-in Dest:Src[:FuncName]    generate function with semantics of use.In (repeatable)
+//	-o file                    output file name (default use_gen.go)
+//	-dir directory             package directory (default current directory)
+//
+// Only the default tag names and tag options of use package are supported,
+// the converters and mappings registered at runtime are not known to the generator.
+// The generated functions do not detect reference cycles (see use.CycleError).
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("usegen: ")
+
+	cfg, err := parseArgs(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cfg.dir, cfg.output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
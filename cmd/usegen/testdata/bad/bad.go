@@ -0,0 +1,65 @@
+package bad
+
+import "database/sql"
+
+type Src struct {
+	F1 bool
+	F2 int
+}
+
+type DestMismatch struct {
+	F1 string `usefrom:""`
+}
+
+type DestOption struct {
+	F2 int `usefrom:",unknownoption"`
+}
+
+type DestMissing struct {
+	F3 *int `usefrom:"Missing"`
+}
+
+type SrcMissing struct {
+	F1 bool `usein:"Missing"`
+}
+
+type DestNull struct {
+	F1 sql.NullString `usefrom:""`
+}
+
+type SrcNull struct {
+	F1 sql.NullString
+}
+
+type Opt struct {
+	v  int
+	ok bool
+}
+
+func (o Opt) UseValue() (any, bool) {
+	return o.v, o.ok
+}
+
+type DestOpt struct {
+	F1 int `usefrom:""`
+}
+
+type SrcOpt struct {
+	F1 Opt
+}
+
+type DestArr struct {
+	F1 [2]Src `usefrom:""`
+}
+
+type SrcArr struct {
+	F1 [2]Src
+}
+
+type DestPtrPtr struct {
+	F1 **string `usefrom:""`
+}
+
+type SrcPtrPtr struct {
+	F1 *string
+}
@@ -0,0 +1,14 @@
+// Package imports uses types of a package outside the standard library.
+package imports
+
+import "github.com/dacz/use/cmd/usegen/internal/example"
+
+type Dest struct {
+	Name    string           `usefrom:""`
+	Address *example.Address `usefrom:""`
+}
+
+type Src struct {
+	Name    *string
+	Address *example.AddressInput
+}
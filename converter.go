@@ -0,0 +1,115 @@
+package use
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+type convKey struct {
+	src  reflect.Type
+	dest reflect.Type
+}
+
+// converters is a registry of functions converting values between types.
+type converters struct {
+	mu  sync.RWMutex
+	fns map[convKey]reflect.Value
+}
+
+func (c *converters) register(fn any) error {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return errors.New("converter must be a function")
+	}
+
+	ft := fv.Type()
+	if ft.NumIn() != 1 || ft.NumOut() < 1 || ft.NumOut() > 2 || ft.IsVariadic() {
+		return fmt.Errorf("converter %s must be func(S) D or func(S) (D, error)", ft)
+	}
+	if ft.NumOut() == 2 && ft.Out(1) != errorType {
+		return fmt.Errorf("converter %s must be func(S) D or func(S) (D, error)", ft)
+	}
+	if ft.In(0).Kind() == reflect.Ptr || ft.Out(0).Kind() == reflect.Ptr {
+		return fmt.Errorf("converter %s must not use pointer types", ft)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fns == nil {
+		c.fns = make(map[convKey]reflect.Value)
+	}
+	c.fns[convKey{src: ft.In(0), dest: ft.Out(0)}] = fv
+	return nil
+}
+
+func (c *converters) lookup(srcT, destT reflect.Type) (reflect.Value, bool) {
+	if c == nil {
+		return reflect.Value{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	fv, ok := c.fns[convKey{src: srcT, dest: destT}]
+	return fv, ok
+}
+
+func (c *converters) has(srcT, destT reflect.Type) bool {
+	_, ok := c.lookup(srcT, destT)
+	return ok
+}
+
+// convert converts non nil v to (dereferenced) destT. The returned value is addressable.
+// Without a registered converter, strings are parsed (formatted) by encoding.TextUnmarshaler
+// (TextMarshaler). ok is false when there is no conversion for the types.
+func (c *converters) convert(v reflect.Value, destT reflect.Type) (cv reflect.Value, ok bool, err error) {
+	sv, ok := baseValue(v)
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+	dt := baseType(destT)
+
+	fn, ok := c.lookup(sv.Type(), dt)
+	if !ok {
+		if sv.Kind() == reflect.Array {
+			return c.convertArray(sv, dt)
+		}
+		return convertText(sv, dt)
+	}
+
+	out := fn.Call([]reflect.Value{sv})
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, true, fmt.Errorf("converting %s to %s: %w", sv.Type(), dt, out[1].Interface().(error))
+	}
+
+	cv = reflect.New(dt).Elem()
+	cv.Set(out[0])
+	return cv, true, nil
+}
+
+// convertArray converts the arrays of the same length element by element,
+// when there is a converter for the element types. Nil source elements (of any pointer level) are left zero.
+func (c *converters) convertArray(sv reflect.Value, dt reflect.Type) (reflect.Value, bool, error) {
+	if sv.Kind() != reflect.Array || dt.Kind() != reflect.Array || sv.Len() != dt.Len() {
+		return reflect.Value{}, false, nil
+	}
+	if se := baseType(sv.Type().Elem()); !c.has(se, dt.Elem()) && !textConvertible(se, dt.Elem()) {
+		return reflect.Value{}, false, nil
+	}
+
+	cv := reflect.New(dt).Elem()
+	for i := 0; i < sv.Len(); i++ {
+		ev := sv.Index(i)
+		if _, ok := baseValue(ev); !ok || isNil(ev) {
+			continue
+		}
+		e, _, err := c.convert(ev, dt.Elem())
+		if err != nil {
+			return reflect.Value{}, true, fmt.Errorf("element %d: %w", i, err)
+		}
+		cv.Index(i).Set(e)
+	}
+	return cv, true, nil
+}
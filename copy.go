@@ -0,0 +1,225 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// run holds the state of a single copy operation (From, In or Auto call).
+type run struct {
+	m         *Mapper
+	mode      mode
+	opts      *options
+	setFields []string
+	undo      []undoEntry
+	journal   bool // record changes even without undo option
+
+	active map[visitKey]bool          // source structs being copied (cycle detection)
+	copied map[visitKey]reflect.Value // copies of source structs (PreserveShared)
+	depth  int
+
+	defaults []string // paths set to default values
+}
+
+func (r *run) apply(dest, src any) ([]string, error) {
+	defer r.setUndo()
+	defer r.setDefaults()
+
+	if err := r.checkMask(dest, src); err != nil {
+		return nil, err
+	}
+	if err := r.checkRequired(dest, src); err != nil {
+		return nil, err
+	}
+
+	if err := r.copy(dest, src, ""); err != nil {
+		return nil, err
+	}
+	return r.setFields, nil
+}
+
+// copy copies fields of src to dest according to the plan of their types,
+// then runs the mapping function registered for the types.
+func (r *run) copy(dest, src any, parentFieldName string) error {
+	destObj, err := newObj(dest)
+	if err != nil {
+		return fmt.Errorf("invalid value of destination object: %w (on path: %q)", err, parentFieldName)
+	}
+
+	srcObj, err := newObj(src)
+	if err != nil {
+		return fmt.Errorf("invalid value of source object: %w (on path: %q)", err, parentFieldName)
+	}
+
+	p, err := r.m.plan(r.mode, destObj.derefType(), srcObj.derefType(), r.opts)
+	if err != nil {
+		return fmt.Errorf("%w (on path: %q)", err, parentFieldName)
+	}
+
+	key := r.enter(destObj, srcObj)
+	defer r.leave(key)
+
+	for _, fr := range p.rules {
+		if err := r.copyField(destObj, srcObj, fr, parentFieldName); err != nil {
+			return err
+		}
+	}
+
+	return r.runMapping(destObj, srcObj, parentFieldName)
+}
+
+func (r *run) copyField(destObj, srcObj *obj, fr *rule, parentFieldName string) error {
+	fieldPath := addToFields(parentFieldName, fr.destName)
+
+	if fr.missing {
+		r.trace(fieldPath, ReasonMissing)
+		return nil
+	}
+
+	if fr.tag.hasDefault {
+		if handled, err := r.applyDefault(destObj, srcObj, fr, fieldPath); handled || err != nil {
+			return err
+		}
+	}
+
+	if !fr.nested {
+		if !r.opts.mask.covers(fieldPath) {
+			r.trace(fieldPath, ReasonMasked)
+			return nil
+		}
+
+		srcVal, _ := srcObj.field(fr.srcName)
+		return r.setRule(destObj, fr, srcVal, fieldPath)
+	}
+
+	// we have sub structs
+	if !r.opts.mask.enters(fieldPath) {
+		r.trace(fieldPath, ReasonMasked)
+		return nil
+	}
+
+	srcVal, _ := srcObj.field(fr.srcName)
+	destVal, _ := destObj.field(fr.destName)
+	if srcVal.Kind() == reflect.Array {
+		return r.copyArray(destVal, srcVal, fr.tag, fieldPath)
+	}
+	return r.copyNested(destVal, srcVal, fr.tag, fieldPath)
+}
+
+// copyNested copies the struct (or reference of any depth to struct) srcVal into destVal,
+// nil destination is allocated.
+func (r *run) copyNested(destVal, srcVal reflect.Value, tg *tag, fieldPath string) error {
+	if isNil(srcVal) {
+		r.trace(fieldPath, ReasonNil)
+		return nil
+	}
+	if !isNil(destVal) && tg.noOverwrite {
+		r.trace(fieldPath, ReasonNoOverwrite)
+		return nil
+	}
+
+	// "present but null" source (like **Struct with nil inner pointer) clears the destination
+	if isNullRef(srcVal) {
+		old := r.snapshot(destVal)
+		clearValue(destVal)
+		r.changed(fieldPath, destVal, old, false)
+		r.setFields = append(r.setFields, fieldPath)
+		r.trace(fieldPath, ReasonCleared)
+		return nil
+	}
+
+	// multi-level pointers are resolved to the pointer to struct
+	for srcVal.Kind() == reflect.Ptr && srcVal.Elem().Kind() == reflect.Ptr {
+		srcVal = srcVal.Elem()
+	}
+	destVal = r.derefDest(destVal, fieldPath)
+	newsrc := refAny(srcVal)
+
+	shared, err := r.visited(newsrc, destVal, fieldPath)
+	if err != nil {
+		return err
+	}
+	if shared.IsValid() {
+		old := r.snapshot(destVal)
+		destVal.Set(shared)
+		r.changed(fieldPath, destVal, old, true)
+		r.trace(fieldPath, ReasonShared)
+		return nil
+	}
+
+	r.allocDest(destVal, fieldPath)
+	r.trace(fieldPath, ReasonNested)
+	return r.copy(refAny(destVal), newsrc, fieldPath)
+}
+
+// setRule sets the destination field of non nested rule from srcVal and records the change.
+func (r *run) setRule(destObj *obj, fr *rule, srcVal reflect.Value, fieldPath string) error {
+	destVal, _ := destObj.field(fr.destName)
+	old := r.snapshot(destVal)
+	wasSet, unchanged, err := r.setField(destObj, fr.destName, srcVal, fr.tag)
+	if err != nil {
+		return fmt.Errorf("failed to set field %q: %w", fieldPath, err)
+	}
+	if wasSet {
+		r.changed(fieldPath, destVal, old, false)
+		r.setFields = append(r.setFields, fieldPath)
+	}
+	if r.opts.tracer != nil {
+		r.trace(fieldPath, setReason(srcVal, wasSet, unchanged))
+	}
+	return nil
+}
+
+// derefDest resolves multi-level pointer destVal to the pointer to struct (allocating nil levels).
+func (r *run) derefDest(destVal reflect.Value, fieldPath string) reflect.Value {
+	for destVal.Kind() == reflect.Ptr && destVal.Type().Elem().Kind() == reflect.Ptr {
+		if destVal.IsNil() {
+			old := r.snapshot(destVal)
+			destVal.Set(reflect.New(destVal.Type().Elem()))
+			r.changed(fieldPath, destVal, old, true)
+		}
+		destVal = destVal.Elem()
+	}
+	return destVal
+}
+
+// allocDest creates the struct referenced by nil destVal.
+func (r *run) allocDest(destVal reflect.Value, fieldPath string) {
+	if !isNil(destVal) {
+		return
+	}
+	old := r.snapshot(destVal)
+	destVal.Set(reflect.New(destVal.Type().Elem()))
+	r.changed(fieldPath, destVal, old, true)
+}
+
+// copyArray copies the arrays of structs element by element, the elements have paths like "Addresses[1]".
+func (r *run) copyArray(destVal, srcVal reflect.Value, tg *tag, fieldPath string) error {
+	for i := 0; i < srcVal.Len(); i++ {
+		elemPath := fmt.Sprintf("%s[%d]", fieldPath, i)
+		if !r.opts.mask.enters(elemPath) {
+			r.trace(elemPath, ReasonMasked)
+			continue
+		}
+		if err := r.copyNested(destVal.Index(i), srcVal.Index(i), tg, elemPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setReason returns the reason of setField result for the tracer.
+func setReason(srcVal reflect.Value, wasSet, unchanged bool) Reason {
+	switch {
+	case isNil(srcVal):
+		return ReasonNil
+	case unchanged:
+		return ReasonUnchanged
+	case !wasSet:
+		return ReasonNoOverwrite
+	case isNullRef(srcVal):
+		return ReasonCleared
+	default:
+		return ReasonSet
+	}
+}
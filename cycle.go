@@ -0,0 +1,104 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CycleError is returned when the source contains a reference cycle (e.g. a tree node
+// pointing to its parent) and PreserveShared option is not used.
+type CycleError struct {
+	Path string // path of the field referencing an already copied source struct
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("reference cycle in source (on path: %q)", e.Path)
+}
+
+// DepthError is returned when the nested structs are deeper than allowed by MaxDepth option.
+type DepthError struct {
+	Path     string // path of the first field exceeding the limit
+	MaxDepth int
+}
+
+func (e *DepthError) Error() string {
+	return fmt.Sprintf("max depth %d exceeded (on path: %q)", e.MaxDepth, e.Path)
+}
+
+// MaxDepth limits the nesting of copied structs, the fields of the top level struct
+// have depth 0, fields of its nested structs depth 1 etc. The copy fails with DepthError
+// when a deeper nested struct should be copied. n <= 0 means no limit (default).
+func MaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
+
+// PreserveShared keeps the shape of the source reference graph: a source struct referenced
+// several times (including cycles) is copied only once and the destination references
+// point to the same copy. Without it, a cycle is reported as CycleError.
+//
+// Example:
+//
+//	type Node struct {
+//		Name   string `usefrom:"Name"`
+//		Parent *Node  `usefrom:"Parent"`
+//	}
+//	_, err := use.From(&dest, &src, use.PreserveShared())
+func PreserveShared() Option {
+	return func(o *options) {
+		o.preserveShared = true
+	}
+}
+
+// visitKey identifies a source struct, the type is needed as a struct and its
+// first field share the address.
+type visitKey struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+func newVisitKey(v reflect.Value) visitKey {
+	return visitKey{ptr: v.Pointer(), t: v.Type()}
+}
+
+// enter marks the source struct as being copied into dest.
+func (r *run) enter(destObj, srcObj *obj) visitKey {
+	key := newVisitKey(srcObj.v)
+	if r.active == nil {
+		r.active = map[visitKey]bool{}
+	}
+	r.active[key] = true
+	if r.opts.preserveShared {
+		if r.copied == nil {
+			r.copied = map[visitKey]reflect.Value{}
+		}
+		r.copied[key] = destObj.v
+	}
+	r.depth++
+	return key
+}
+
+func (r *run) leave(key visitKey) {
+	delete(r.active, key)
+	r.depth--
+}
+
+// visited checks the nested source struct before it is copied on fieldPath.
+// It returns the copy to be referenced by the destination field, when the source was
+// already copied and shared structure is preserved.
+func (r *run) visited(src any, destField reflect.Value, fieldPath string) (reflect.Value, error) {
+	key := newVisitKey(reflect.ValueOf(src))
+	if r.opts.preserveShared {
+		if d, ok := r.copied[key]; ok && destField.Type() == d.Type() {
+			return d, nil
+		}
+	}
+	if r.active[key] {
+		return reflect.Value{}, &CycleError{Path: fieldPath}
+	}
+	if r.opts.maxDepth > 0 && r.depth > r.opts.maxDepth {
+		return reflect.Value{}, &DepthError{Path: fieldPath, MaxDepth: r.opts.maxDepth}
+	}
+	return reflect.Value{}, nil
+}
@@ -0,0 +1,87 @@
+package use
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cycleNode struct {
+	Name   string     `usefrom:"" usein:""`
+	Parent *cycleNode `usefrom:"" usein:""`
+	Child  *cycleNode `usefrom:"" usein:""`
+}
+
+func TestCycle(t *testing.T) {
+	newSrc := func() *cycleNode {
+		root := &cycleNode{Name: "root"}
+		root.Child = &cycleNode{Name: "child", Parent: root}
+		return root
+	}
+
+	t.Run("error", func(t *testing.T) {
+		for name, fn := range map[string]func(dest, src any, opts ...Option) ([]string, error){
+			"from": From,
+			"in":   In,
+		} {
+			t.Run(name, func(t *testing.T) {
+				dest := cycleNode{}
+				var undo Undo
+				_, err := fn(&dest, newSrc(), WithUndo(&undo))
+
+				var cerr *CycleError
+				require.True(t, errors.As(err, &cerr))
+				require.Equal(t, "Child.Parent", cerr.Path)
+
+				undo()
+				require.Equal(t, cycleNode{}, dest)
+			})
+		}
+	})
+
+	t.Run("preserve shared", func(t *testing.T) {
+		dest := cycleNode{}
+		setFields, err := From(&dest, newSrc(), PreserveShared())
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name", "Child.Name"}, setFields)
+		require.Equal(t, "child", dest.Child.Name)
+		require.Same(t, &dest, dest.Child.Parent)
+	})
+
+	t.Run("shared without cycle", func(t *testing.T) {
+		type Pair struct {
+			A *cycleNode `usefrom:""`
+			B *cycleNode `usefrom:""`
+		}
+		shared := &cycleNode{Name: "shared"}
+
+		dest := Pair{}
+		_, err := From(&dest, &Pair{A: shared, B: shared})
+		require.NoError(t, err)
+		require.NotSame(t, dest.A, dest.B)
+
+		dest = Pair{}
+		_, err = From(&dest, &Pair{A: shared, B: shared}, PreserveShared())
+		require.NoError(t, err)
+		require.Same(t, dest.A, dest.B)
+		require.Equal(t, "shared", dest.A.Name)
+	})
+}
+
+func TestMaxDepth(t *testing.T) {
+	src := &cycleNode{Name: "1", Child: &cycleNode{Name: "2", Child: &cycleNode{Name: "3"}}}
+
+	dest := cycleNode{}
+	_, err := From(&dest, src, MaxDepth(2))
+	require.NoError(t, err)
+	require.Equal(t, "3", dest.Child.Child.Name)
+
+	dest = cycleNode{}
+	_, err = From(&dest, src, MaxDepth(1))
+	var derr *DepthError
+	require.True(t, errors.As(err, &derr))
+	require.Equal(t, "Child.Child", derr.Path)
+	require.Equal(t, 1, derr.MaxDepth)
+	require.Nil(t, dest.Child.Child)
+}
@@ -0,0 +1,136 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Defaults stores to *paths the paths of the fields set to their `default=` values by the call
+// (a subset of setFields), so the defaults can be told apart from the copied values.
+//
+// Example:
+//
+//	var defaults []string
+//	setFields, err := use.From(&entity, &input, use.Defaults(&defaults))
+func Defaults(paths *[]string) Option {
+	return func(o *options) {
+		o.defaults = paths
+	}
+}
+
+// setDefaults hands the paths set to default values to the caller.
+func (r *run) setDefaults() {
+	if r.opts.defaults != nil {
+		*r.opts.defaults = r.defaults
+	}
+}
+
+// parseString parses s (the literal of `default=` tag option or a form value) into the (dereferenced)
+// type t. Types implementing encoding.TextUnmarshaler are parsed by it, basic types by strconv.
+func parseString(t reflect.Type, s string) (reflect.Value, error) {
+	t = baseType(t)
+	if cv, ok, err := convertText(reflect.ValueOf(s), t); ok {
+		return cv, err
+	}
+
+	v := reflect.New(t).Elem()
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("type %s is not supported", t)
+	}
+	return v, nil
+}
+
+// checkDefault verifies the default value of the tag can be parsed into the destination field.
+func checkDefault(tg *tag, destField reflect.StructField) error {
+	if !tg.hasDefault {
+		return nil
+	}
+	if _, err := parseString(destField.Type, tg.defValue); err != nil {
+		return fmt.Errorf("invalid default value %q of field %q: %w", tg.defValue, destField.Name, err)
+	}
+	return nil
+}
+
+// applyDefault sets the default value when the source does not provide the field
+// and the destination is zero. handled is true when the source does not provide the field.
+func (r *run) applyDefault(destObj, srcObj *obj, fr *rule, fieldPath string) (handled bool, err error) {
+	if srcVal, ok := srcObj.field(fr.srcName); ok && !isNil(srcVal) {
+		return false, nil
+	}
+	return true, r.setDefault(destObj, fr, fieldPath)
+}
+
+// setDefault sets the default value of the rule when the destination is zero.
+func (r *run) setDefault(destObj *obj, fr *rule, fieldPath string) error {
+	if !r.opts.mask.covers(fieldPath) {
+		r.trace(fieldPath, ReasonMasked)
+		return nil
+	}
+
+	destVal, _ := destObj.field(fr.destName)
+	if !destVal.IsZero() {
+		r.trace(fieldPath, ReasonNil)
+		return nil
+	}
+
+	// parsed for every use, the value must not be shared between destinations
+	v, err := parseString(destVal.Type(), fr.tag.defValue)
+	if err != nil {
+		return fmt.Errorf("failed to set default of field %q: %w", fieldPath, err)
+	}
+
+	if r.opts.onlyChanged && valuesEqual(destVal, v) {
+		r.trace(fieldPath, ReasonUnchanged)
+		return nil
+	}
+
+	old := r.snapshot(destVal)
+	if err := assign(destVal, v); err != nil {
+		return fmt.Errorf("failed to set default of field %q: %w", fieldPath, err)
+	}
+	r.changed(fieldPath, destVal, old, false)
+	r.setFields = append(r.setFields, fieldPath)
+	r.defaults = append(r.defaults, fieldPath)
+	r.trace(fieldPath, ReasonDefault)
+	return nil
+}
@@ -0,0 +1,120 @@
+package use
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	type Entity struct {
+		Status  string        `usefrom:",default=active"`
+		Limit   int           `usefrom:",default=30"`
+		Ratio   *float64      `usefrom:",default=0.5"`
+		Enabled bool          `usefrom:",default=true"`
+		Timeout time.Duration `usefrom:",default=1m30s"`
+		Level   testLevel     `usefrom:",default=low"`
+		Name    string        `usefrom:",default=anonymous"`
+	}
+
+	type Input struct {
+		Status *string
+		Limit  *int
+		Ratio  *float64
+		Name   *string
+		// Enabled, Timeout and Level are missing
+	}
+
+	t.Run("missing and nil", func(t *testing.T) {
+		dest := Entity{Name: "kept"}
+		setFields, err := From(&dest, &Input{Limit: asRef(10)})
+		require.NoError(t, err)
+		require.Equal(t, Entity{
+			Status:  "active",
+			Limit:   10,
+			Ratio:   asRef(0.5),
+			Enabled: true,
+			Timeout: 90 * time.Second,
+			Level:   testLevelLow,
+			Name:    "kept", // not zero
+		}, dest)
+		sort.Strings(setFields)
+		require.Equal(t, []string{"Enabled", "Level", "Limit", "Ratio", "Status", "Timeout"}, setFields)
+	})
+
+	t.Run("defaults report", func(t *testing.T) {
+		dest := Entity{Name: "kept"}
+		var defaults []string
+		setFields, err := From(&dest, &Input{Limit: asRef(10)}, Defaults(&defaults))
+		require.NoError(t, err)
+		require.Contains(t, setFields, "Limit")
+		sort.Strings(defaults)
+		require.Equal(t, []string{"Enabled", "Level", "Ratio", "Status", "Timeout"}, defaults)
+
+		_, err = FromEnv(&Entity{}, "APP", EnvLookup(func(name string) (string, bool) {
+			return "busy", name == "APP_STATUS"
+		}), Defaults(&defaults))
+		require.NoError(t, err)
+		sort.Strings(defaults)
+		require.Equal(t, []string{"Enabled", "Level", "Limit", "Name", "Ratio", "Timeout"}, defaults)
+	})
+
+	t.Run("not shared", func(t *testing.T) {
+		d1, d2 := Entity{}, Entity{}
+		_, err := From(&d1, &Input{})
+		require.NoError(t, err)
+		_, err = From(&d2, &Input{})
+		require.NoError(t, err)
+		require.NotSame(t, d1.Ratio, d2.Ratio)
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		dest := Entity{}
+		setFields, err := From(&dest, &Input{}, Mask("Status"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"Status"}, setFields)
+		require.Equal(t, Entity{Status: "active"}, dest)
+	})
+
+	t.Run("in", func(t *testing.T) {
+		type Patch struct {
+			Status *string `usein:",default=pending"`
+		}
+		dest := Entity{}
+		_, err := In(&dest, &Patch{})
+		require.NoError(t, err)
+		require.Equal(t, "pending", dest.Status)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		type Dest struct {
+			Limit int `usefrom:",default=many"`
+		}
+		_, err := From(&Dest{}, &Input{Limit: asRef(1)})
+		require.ErrorContains(t, err, `invalid default value "many" of field "Limit"`)
+	})
+
+	t.Run("tracked as default", func(t *testing.T) {
+		dest := Entity{}
+		tr := Track(&dest)
+		_, err := tr.From(&Input{Status: asRef("blocked")})
+		require.NoError(t, err)
+
+		changes := map[string]Change{}
+		for _, ch := range tr.Dirty() {
+			changes[ch.Path] = ch
+		}
+		require.Equal(t, Change{Path: "Status", Old: "", New: "blocked"}, changes["Status"])
+		require.Equal(t, Change{Path: "Limit", Old: 0, New: 30, Default: true}, changes["Limit"])
+
+		_, err = tr.From(&Input{Limit: asRef(40)}, Mask("Limit"))
+		require.NoError(t, err)
+		for _, ch := range tr.Dirty() {
+			if ch.Path == "Limit" {
+				require.Equal(t, Change{Path: "Limit", Old: 0, New: 40}, ch)
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package use
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvLookup makes FromEnv read the variables by lookup instead of os.LookupEnv (e.g. in tests).
+func EnvLookup(lookup func(name string) (string, bool)) Option {
+	return func(o *options) {
+		o.envLookup = lookup
+	}
+}
+
+// FromEnv applies the environment variables to dest (e.g. a configuration struct). The variable
+// names are the source names in `usefrom` tag in upper snake case joined by "_" with the prefix
+// and the names of nested structs, e.g. field Database.Host with prefix "APP" is set
+// from APP_DATABASE_HOST.
+//
+// Slices are split by commas. The variables are not listed, so the fields of recursive struct
+// types are not looked up.
+//
+// Example:
+//
+//	var cfg Config
+//	setFields, err := use.FromEnv(&cfg, "APP")
+func FromEnv(dest any, prefix string, opts ...Option) (setFields []string, err error) {
+	return defaultMapper.FromEnv(dest, prefix, opts...)
+}
+
+// FromEnv is like the package level FromEnv, using the mapper configuration.
+func (m *Mapper) FromEnv(dest any, prefix string, opts ...Option) (setFields []string, err error) {
+	r := m.newRun(envMode, opts)
+	src := envSource{prefix: strings.TrimSuffix(prefix, "_"), lookup: r.opts.envLookup}
+	if src.lookup == nil {
+		src.lookup = os.LookupEnv
+	}
+
+	return r.fromKeys(dest, src)
+}
+
+// envSource looks up the variables named like PREFIX_NESTED_FIELD.
+type envSource struct {
+	prefix string
+	lookup func(string) (string, bool)
+}
+
+func (e envSource) key(parent, name string) string {
+	if parent == "" {
+		parent = e.prefix
+	}
+	name = strings.ToUpper(toSnakeCase(name))
+	if parent == "" {
+		return name
+	}
+	return parent + "_" + name
+}
+
+func (e envSource) get(key string, slice bool) []string {
+	v, ok := e.lookup(key)
+	if !ok {
+		return nil
+	}
+	if slice {
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	}
+	return []string{v}
+}
+
+// mayHave is always true, the variables are not listed.
+func (e envSource) mayHave(string) bool {
+	return true
+}
@@ -0,0 +1,153 @@
+package use
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	type Database struct {
+		Host     string `usefrom:""`
+		Port     int    `usefrom:""`
+		MaxConns *int   `usefrom:""`
+	}
+
+	type Node struct {
+		Name string `usefrom:""`
+		Next *Node  `usefrom:""`
+	}
+
+	type Config struct {
+		Name     string        `usefrom:",nooverwrite"`
+		Debug    bool          `usefrom:""`
+		Timeout  time.Duration `usefrom:""`
+		Hosts    []string      `usefrom:""`
+		Level    testLevel     `usefrom:"log_level"`
+		Database *Database     `usefrom:""`
+		Cache    *Database     `usefrom:""`
+		Node     Node          `usefrom:""`
+		Secret   string
+	}
+
+	env := map[string]string{
+		"APP_NAME":               "new",
+		"APP_DEBUG":              "true",
+		"APP_TIMEOUT":            "5s",
+		"APP_HOSTS":              "a,b",
+		"APP_LOG_LEVEL":          "low",
+		"APP_DATABASE_HOST":      "db",
+		"APP_DATABASE_PORT":      "5432",
+		"APP_DATABASE_MAX_CONNS": "",
+		"APP_NODE_NAME":          "root",
+		"APP_SECRET":             "secret",
+	}
+	lookup := EnvLookup(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})
+
+	cfg := Config{Name: "old"}
+	setFields, err := FromEnv(&cfg, "APP", lookup)
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Name:     "old",
+		Debug:    true,
+		Timeout:  5 * time.Second,
+		Hosts:    []string{"a", "b"},
+		Level:    testLevelLow,
+		Database: &Database{Host: "db", Port: 5432},
+		Node:     Node{Name: "root"},
+	}, cfg)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Database.Host", "Database.Port", "Debug", "Hosts", "Level", "Node.Name", "Timeout"}, setFields)
+
+	t.Run("prefix", func(t *testing.T) {
+		var db Database
+		setFields, err := FromEnv(&db, "APP_DATABASE_", lookup)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Host", "Port"}, setFields)
+		require.Equal(t, Database{Host: "db", Port: 5432}, db)
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		var cfg Config
+		setFields, err := FromEnv(&cfg, "APP", lookup, Mask("Database.Host"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"Database.Host"}, setFields)
+
+		_, err = FromEnv(&cfg, "APP", lookup, Mask("Database.Nope"))
+		require.ErrorContains(t, err, `mask path "Database.Nope" does not match any field`)
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		cfg := Config{}
+		_, err := FromEnv(&cfg, "APP", EnvLookup(func(name string) (string, bool) {
+			switch name {
+			case "APP_DEBUG":
+				return "maybe", true
+			case "APP_DATABASE_PORT":
+				return "x", true
+			case "APP_NAME":
+				return "name", true
+			}
+			return "", false
+		}))
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Len(t, parseErr.Values, 2)
+		require.Contains(t, parseErr.Values, "APP_DEBUG")
+		require.Contains(t, parseErr.Values, "APP_DATABASE_PORT")
+		require.ErrorContains(t, err, "invalid values: APP_DATABASE_PORT: ")
+		require.Equal(t, Config{}, cfg) // nothing is copied
+	})
+
+	t.Run("default and required", func(t *testing.T) {
+		type DB struct {
+			Host string `usefrom:",default=localhost"`
+			Name string `usefrom:",required"`
+		}
+		type Settings struct {
+			ID    string `usefrom:",required"`
+			Port  int    `usefrom:",default=8080"`
+			DB    *DB    `usefrom:""`
+			Cache *DB    `usefrom:""`
+		}
+
+		var s Settings
+		_, err := FromEnv(&s, "APP", EnvLookup(func(string) (string, bool) { return "", false }))
+		var reqErr *RequiredError
+		require.True(t, errors.As(err, &reqErr))
+		require.Equal(t, []string{"ID"}, reqErr.Paths) // fields of nested structs without variables are not required
+		require.Equal(t, Settings{}, s)
+
+		vars := map[string]string{"APP_ID": "x", "APP_DB_NAME": "db"}
+		setFields, err := FromEnv(&s, "APP", EnvLookup(func(name string) (string, bool) {
+			v, ok := vars[name]
+			return v, ok
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"ID", "Port", "DB.Host", "DB.Name"}, setFields)
+		require.Equal(t, Settings{ID: "x", Port: 8080, DB: &DB{Host: "localhost", Name: "db"}}, s)
+
+		vars = map[string]string{"APP_ID": "x", "APP_DB_HOST": "h"}
+		_, err = FromEnv(&Settings{}, "APP", EnvLookup(func(name string) (string, bool) {
+			v, ok := vars[name]
+			return v, ok
+		}))
+		require.True(t, errors.As(err, &reqErr))
+		require.Equal(t, []string{"DB.Name"}, reqErr.Paths)
+	})
+
+	t.Run("process environment", func(t *testing.T) {
+		t.Setenv("USE_TEST_DATABASE_HOST", "localhost")
+		var cfg Config
+		setFields, err := FromEnv(&cfg, "USE_TEST")
+		require.NoError(t, err)
+		require.Equal(t, []string{"Database.Host"}, setFields)
+	})
+}
@@ -0,0 +1,67 @@
+package use
+
+import (
+	"flag"
+	"strings"
+)
+
+// FromFlags applies the flags explicitly set on the command line (fs must be parsed) to dest,
+// the defaults of flags not passed are ignored. The flag names are the source names in `usefrom`
+// tag in dash case joined by "." with the names of nested structs, e.g. field Database.MaxConns
+// is set from flag -database.max-conns.
+//
+// The values are taken from String method of flag.Value, slices are split by commas.
+//
+// Example:
+//
+//	fs := flag.NewFlagSet("app", flag.ExitOnError)
+//	fs.String("database.host", "localhost", "database host")
+//	fs.Parse(os.Args[1:])
+//	setFields, err := use.FromFlags(&cfg, fs)
+func FromFlags(dest any, fs *flag.FlagSet, opts ...Option) (setFields []string, err error) {
+	return defaultMapper.FromFlags(dest, fs, opts...)
+}
+
+// FromFlags is like the package level FromFlags, using the mapper configuration.
+func (m *Mapper) FromFlags(dest any, fs *flag.FlagSet, opts ...Option) (setFields []string, err error) {
+	src := flagSource{}
+	fs.Visit(func(f *flag.Flag) {
+		src[f.Name] = f.Value.String()
+	})
+
+	return m.newRun(flagMode, opts).fromKeys(dest, src)
+}
+
+// flagSource are the values of the set flags by the flag names.
+type flagSource map[string]string
+
+func (f flagSource) key(parent, name string) string {
+	name = strings.ReplaceAll(toSnakeCase(name), "_", "-")
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func (f flagSource) get(key string, slice bool) []string {
+	v, ok := f[key]
+	if !ok {
+		return nil
+	}
+	if slice {
+		if v == "" {
+			return nil
+		}
+		return strings.Split(v, ",")
+	}
+	return []string{v}
+}
+
+func (f flagSource) mayHave(key string) bool {
+	for name := range f {
+		if strings.HasPrefix(name, key+".") {
+			return true
+		}
+	}
+	return false
+}
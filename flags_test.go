@@ -0,0 +1,97 @@
+package use
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFlags(t *testing.T) {
+	type Database struct {
+		Host     string `usefrom:""`
+		MaxConns int    `usefrom:""`
+	}
+
+	type Config struct {
+		Name     string        `usefrom:",nooverwrite"`
+		Debug    bool          `usefrom:""`
+		Timeout  time.Duration `usefrom:""`
+		Port     int           `usefrom:""`
+		Hosts    []string      `usefrom:""`
+		Database *Database     `usefrom:"db"`
+		Cache    *Database     `usefrom:""`
+	}
+
+	newFlags := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.String("name", "", "")
+		fs.Bool("debug", false, "")
+		fs.Duration("timeout", time.Second, "")
+		fs.Int("port", 80, "")
+		fs.String("hosts", "", "")
+		fs.String("db.host", "localhost", "")
+		fs.String("db.max-conns", "10", "")
+		fs.String("cache.host", "localhost", "")
+		return fs
+	}
+
+	fs := newFlags()
+	require.NoError(t, fs.Parse([]string{"-name", "new", "-debug", "-timeout", "5s", "-hosts", "a,b", "-db.max-conns", "20"}))
+
+	cfg := Config{Name: "old", Port: 8080}
+	setFields, err := FromFlags(&cfg, fs)
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Name:     "old",
+		Debug:    true,
+		Timeout:  5 * time.Second,
+		Port:     8080, // the default is not applied
+		Hosts:    []string{"a", "b"},
+		Database: &Database{MaxConns: 20},
+	}, cfg)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Database.MaxConns", "Debug", "Hosts", "Timeout"}, setFields)
+
+	t.Run("default and required", func(t *testing.T) {
+		type Dest struct {
+			Name string `usefrom:",required"`
+			Port int    `usefrom:",default=8080"`
+		}
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("name", "default name", "")
+		fs.Int("port", 80, "")
+		require.NoError(t, fs.Parse(nil))
+
+		_, err := FromFlags(&Dest{}, fs)
+		var reqErr *RequiredError
+		require.True(t, errors.As(err, &reqErr))
+		require.Equal(t, []string{"Name"}, reqErr.Paths)
+
+		require.NoError(t, fs.Parse([]string{"-name", "n"}))
+		dest := Dest{}
+		setFields, err := FromFlags(&dest, fs)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name", "Port"}, setFields)
+		require.Equal(t, Dest{Name: "n", Port: 8080}, dest)
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		fs := newFlags()
+		require.NoError(t, fs.Parse([]string{"-debug", "-db.max-conns", "x"}))
+
+		cfg := Config{}
+		_, err := FromFlags(&cfg, fs)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Len(t, parseErr.Values, 1)
+		require.ErrorContains(t, err, "invalid values: db.max-conns: ")
+		require.Equal(t, Config{}, cfg) // nothing is copied
+	})
+}
@@ -0,0 +1,70 @@
+package use
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FromForm applies the form values (e.g. parsed HTML form) to dest. The keys are the source names
+// in `usefrom` tag (the same name has precedence, otherwise the key is matched case-insensitively),
+// the fields of nested structs have dotted keys like "address.city".
+//
+// Slices are filled from repeated keys, other fields use the first value. Booleans also accept
+// "on" (checked HTML checkbox) and time values the formats of HTML date inputs ("2006-01-02",
+// "2006-01-02T15:04").
+//
+// Example:
+//
+//	if err := req.ParseForm(); err != nil {
+//		return err
+//	}
+//	setFields, err := use.FromForm(&entity, req.PostForm)
+func FromForm(dest any, vals url.Values, opts ...Option) (setFields []string, err error) {
+	return defaultMapper.FromForm(dest, vals, opts...)
+}
+
+// FromForm is like the package level FromForm, using the mapper configuration.
+func (m *Mapper) FromForm(dest any, vals url.Values, opts ...Option) (setFields []string, err error) {
+	return m.newRun(formMode, opts).fromKeys(dest, newFormKeys(vals))
+}
+
+// formKeys looks up the form values by keys, matching them case-insensitively when there is no exact match.
+type formKeys struct {
+	vals   url.Values
+	folded map[string]string // lower case key to the key
+}
+
+func newFormKeys(vals url.Values) formKeys {
+	folded := make(map[string]string, len(vals))
+	for k := range vals {
+		folded[strings.ToLower(k)] = k
+	}
+	return formKeys{vals: vals, folded: folded}
+}
+
+func (f formKeys) key(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func (f formKeys) get(key string, _ bool) []string {
+	if vs, ok := f.vals[key]; ok {
+		return vs
+	}
+	if k, ok := f.folded[strings.ToLower(key)]; ok {
+		return f.vals[k]
+	}
+	return nil
+}
+
+func (f formKeys) mayHave(key string) bool {
+	prefix := strings.ToLower(key) + "."
+	for k := range f.folded {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package use
+
+import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromForm(t *testing.T) {
+	type Address struct {
+		City   string  `usefrom:"city"`
+		Street *string `usefrom:"street"`
+	}
+
+	type Entity struct {
+		Name    string        `usefrom:"name,transform=trim"`
+		Email   *string       `usefrom:"email,nooverwrite"`
+		Age     int           `usefrom:"age"`
+		Active  bool          `usefrom:"active"`
+		Score   *float64      `usefrom:"score"`
+		Born    time.Time     `usefrom:"born"`
+		At      *time.Time    `usefrom:"at"`
+		Level   testLevel     `usefrom:"level"`
+		Tags    []string      `usefrom:"tag"`
+		IDs     []int         `usefrom:"id"`
+		Limit   sql.NullInt64 `usefrom:"limit"`
+		Note    string        `usefrom:"note"`
+		Count   int           `usefrom:"count"`
+		Address *Address      `usefrom:"address"`
+		Billing *Address      `usefrom:"billing"`
+	}
+
+	newObj := func() Entity {
+		return Entity{Email: asRef("old email"), Note: "old note", Count: 7}
+	}
+
+	vals := url.Values{
+		"name":         {" John "},
+		"email":        {"new email"},
+		"AGE":          {"42"},
+		"active":       {"true"},
+		"score":        {"1.5"},
+		"born":         {"2000-01-02"},
+		"at":           {"2024-05-01T10:00:00Z"},
+		"level":        {"high"},
+		"tag":          {"a", "b"},
+		"id":           {"1", "", "3"},
+		"limit":        {"10"},
+		"count":        {""},
+		"address.city": {"Prague"},
+		"unknown":      {"x"},
+	}
+
+	dest := newObj()
+	var undo Undo
+	setFields, err := FromForm(&dest, vals, WithUndo(&undo))
+	require.NoError(t, err)
+	require.Equal(t, Entity{
+		Name:    "John",
+		Email:   asRef("old email"),
+		Age:     42,
+		Active:  true,
+		Score:   asRef(1.5),
+		Born:    time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		At:      asRef(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+		Level:   testLevelHigh,
+		Tags:    []string{"a", "b"},
+		IDs:     []int{1, 3},
+		Limit:   sql.NullInt64{Int64: 10, Valid: true},
+		Note:    "old note",
+		Count:   7,
+		Address: &Address{City: "Prague"},
+	}, dest)
+	sort.Strings(setFields)
+	require.Equal(t, []string{
+		"Active", "Address.City", "Age", "At", "Born", "IDs", "Level", "Limit", "Name", "Score", "Tags",
+	}, setFields)
+
+	undo()
+	require.Equal(t, newObj(), dest)
+
+	t.Run("mask", func(t *testing.T) {
+		dest := Entity{}
+		setFields, err := FromForm(&dest, vals, Mask("Age", "Address.City"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"Age", "Address.City"}, setFields)
+		require.Equal(t, Entity{Age: 42, Address: &Address{City: "Prague"}}, dest)
+
+		_, err = FromForm(&dest, vals, Mask("Nope"))
+		require.ErrorContains(t, err, `mask path "Nope" does not match any field`)
+		_, err = FromForm(&dest, vals, Mask("Address.Nope"))
+		require.ErrorContains(t, err, `mask path "Address.Nope" does not match any field`)
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		type Dest struct {
+			Name string `usefrom:"name"`
+			Note string `usefrom:"note"`
+		}
+
+		dest := Dest{}
+		setFields, err := FromForm(&dest, url.Values{"": {"evil"}, "name": {"n"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name"}, setFields)
+		require.Equal(t, Dest{Name: "n"}, dest)
+	})
+
+	t.Run("checkbox", func(t *testing.T) {
+		type Dest struct {
+			Active  bool   `usefrom:"active"`
+			Agree   *bool  `usefrom:"agree"`
+			Options []bool `usefrom:"option"`
+		}
+
+		dest := Dest{}
+		setFields, err := FromForm(&dest, url.Values{"active": {"on"}, "agree": {"on"}, "option": {"on", "false"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Active", "Agree", "Options"}, setFields)
+		require.Equal(t, Dest{Active: true, Agree: asRef(true), Options: []bool{true, false}}, dest)
+
+		_, err = FromEnv(&dest, "", EnvLookup(func(name string) (string, bool) { return "on", name == "ACTIVE" }))
+		require.Error(t, err) // only forms
+	})
+
+	t.Run("default and required", func(t *testing.T) {
+		type Dest struct {
+			Name  string `usefrom:"name,required"`
+			Limit int    `usefrom:"limit,default=10"`
+		}
+
+		_, err := FromForm(&Dest{}, url.Values{"limit": {"5"}})
+		var reqErr *RequiredError
+		require.True(t, errors.As(err, &reqErr))
+		require.Equal(t, []string{"Name"}, reqErr.Paths)
+
+		dest := Dest{}
+		setFields, err := FromForm(&dest, url.Values{"name": {"n"}, "limit": {""}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name", "Limit"}, setFields)
+		require.Equal(t, Dest{Name: "n", Limit: 10}, dest)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		type Dest struct {
+			Count testOption[int]     `usefrom:"count"`
+			Name  *testOption[string] `usefrom:"name"`
+		}
+
+		dest := Dest{}
+		setFields, err := FromForm(&dest, url.Values{"count": {"42"}, "name": {"x"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Count", "Name"}, setFields)
+		require.Equal(t, Dest{Count: some(42), Name: asRef(some("x"))}, dest)
+
+		type Unknown struct {
+			Value testMaybe[int] `usefrom:"value"`
+		}
+		_, err = FromForm(&Unknown{}, url.Values{"value": {"1"}})
+		require.ErrorContains(t, err, "unknown value type of optional")
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		dest := newObj()
+		_, err := FromForm(&dest, url.Values{
+			"name":         {"John"},
+			"age":          {"x"},
+			"id":           {"1", "y"},
+			"address.city": {"Prague"},
+			"born":         {"yesterday"},
+		})
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Len(t, parseErr.Values, 3)
+		require.Contains(t, parseErr.Values, "age")
+		require.Contains(t, parseErr.Values, "id")
+		require.Contains(t, parseErr.Values, "born")
+		require.ErrorContains(t, err, "invalid values: age: ")
+		require.Equal(t, newObj(), dest) // nothing is copied
+	})
+}
@@ -0,0 +1,298 @@
+package use
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// DecodeJSON applies the JSON object read from r to dest without an intermediate source struct.
+// Every present key is applied to the dest field with the key as the source name in `usefrom`
+// tag (the same name has precedence, otherwise the key is matched case-insensitively),
+// nested objects are applied to nested structs recursively.
+//
+// JSON null behaves like nil source field and absent keys are never touched (as missing
+// source fields with `omitmissing`). Unknown keys are ignored. The object is decoded in one pass
+// and the changes are reverted when it is invalid or required fields are missing, so nothing is copied.
+//
+// Example:
+//
+//	setFields, err := use.DecodeJSON(&entity, req.Body)
+func DecodeJSON(dest any, r io.Reader, opts ...Option) (setFields []string, err error) {
+	return defaultMapper.DecodeJSON(dest, r, opts...)
+}
+
+// DecodeJSON is like the package level DecodeJSON, using the mapper configuration.
+func (m *Mapper) DecodeJSON(dest any, r io.Reader, opts ...Option) (setFields []string, err error) {
+	return m.newRun(jsonMode, opts).decodeJSON(dest, r)
+}
+
+func (r *run) decodeJSON(dest any, rd io.Reader) ([]string, error) {
+	defer r.setUndo()
+	defer r.setDefaults()
+
+	destObj, err := newObj(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of destination object: %w", err)
+	}
+	if err := r.checkMaskTypes(destObj.derefType(), nil); err != nil {
+		return nil, err
+	}
+
+	// the changes are recorded to be reverted on error, the events are sent when they are kept
+	r.journal = true
+	tracer := r.opts.tracer
+	var events []Event
+	if tracer != nil {
+		r.opts.tracer = TracerFunc(func(e Event) { events = append(events, e) })
+	}
+
+	var missing []string
+	err = r.decodeRoot(json.NewDecoder(rd), destObj, &missing)
+	if err == nil && len(missing) > 0 {
+		err = &RequiredError{Paths: missing}
+	}
+	r.opts.tracer = tracer
+	if err != nil {
+		r.revert()
+		return nil, err
+	}
+
+	for _, e := range events {
+		tracer.Trace(e)
+	}
+	return r.setFields, nil
+}
+
+// decodeRoot applies the top level object to destObj.
+func (r *run) decodeRoot(dec *json.Decoder, destObj *obj, missing *[]string) error {
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decoding JSON: %w", err)
+	}
+	if t != json.Delim('{') {
+		return errors.New("decoding JSON: value must be an object")
+	}
+	return r.decodeObject(dec, destObj, "", missing)
+}
+
+// missingJSON appends to missing the paths of required fields not provided by the object raw
+// (including objects of nested structs present in raw), for objects which are not applied.
+func (r *run) missingJSON(destT reflect.Type, raw json.RawMessage, path string, missing *[]string) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return // reported by decoding
+	}
+	p, err := r.m.plan(jsonMode, destT, nil, r.opts)
+	if err != nil {
+		return // reported by decoding
+	}
+
+	provided := map[*rule]json.RawMessage{}
+	for key, v := range fields {
+		if fr := p.keyRule(key); fr != nil && !bytes.Equal(v, []byte("null")) {
+			provided[fr] = v
+		}
+	}
+
+	for _, fr := range p.rules {
+		fieldPath := addToFields(path, fr.destName)
+		if !r.opts.mask.enters(fieldPath) {
+			continue
+		}
+		v, ok := provided[fr]
+		if !ok {
+			if fr.tag.required {
+				*missing = append(*missing, fieldPath)
+			}
+			continue
+		}
+		if fr.nested {
+			sf, _ := destT.FieldByName(fr.destName)
+			r.missingJSON(baseType(sf.Type), v, fieldPath, missing)
+		}
+	}
+}
+
+// isJSONNested reports if a JSON object is applied to the field recursively.
+// Types decoding themselves (like time.Time) and structs without exported fields are set as values.
+func isJSONNested(t reflect.Type) bool {
+	if !containsStructOrPtrToStruct(t) || !hasExportedFields(t) {
+		return false
+	}
+	t = baseType(t)
+	return !implements(t, jsonUnmarshalerType) && !implements(t, textUnmarshalerType)
+}
+
+// decodeObject applies the object (its opening delimiter was read) to destObj and appends
+// to missing the paths of required fields not provided.
+func (r *run) decodeObject(dec *json.Decoder, destObj *obj, path string, missing *[]string) error {
+	p, err := r.m.plan(jsonMode, destObj.derefType(), nil, r.opts)
+	if err != nil {
+		return fmt.Errorf("%w (on path: %q)", err, path)
+	}
+
+	provided := map[*rule]bool{}
+	nestedMissing := map[*rule][]string{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decoding JSON: %w (on path: %q)", err, path)
+		}
+		key, _ := t.(string)
+
+		fr := p.keyRule(key)
+		if fr == nil {
+			if err := skipJSON(dec); err != nil {
+				return fmt.Errorf("decoding JSON: %w (on path: %q)", err, path)
+			}
+			continue
+		}
+
+		var fm []string
+		ok, err := r.decodeField(dec, destObj, fr, addToFields(path, fr.destName), &fm)
+		if err != nil {
+			return err
+		}
+		provided[fr] = ok
+		nestedMissing[fr] = fm
+	}
+
+	if _, err := dec.Token(); err != nil { // closing delimiter
+		return fmt.Errorf("decoding JSON: %w (on path: %q)", err, path)
+	}
+
+	// in the order of the fields, like in From
+	for _, fr := range p.rules {
+		fieldPath := addToFields(path, fr.destName)
+		if provided[fr] {
+			*missing = append(*missing, nestedMissing[fr]...)
+			continue
+		}
+		if fr.tag.required && r.opts.mask.enters(fieldPath) {
+			*missing = append(*missing, fieldPath)
+		}
+		if fr.tag.hasDefault && !fr.nested {
+			if err := r.setDefault(destObj, fr, fieldPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeField decodes the value of the rule key, provided is false when the value is null.
+// The missing required fields of a nested struct are appended to missing.
+func (r *run) decodeField(dec *json.Decoder, destObj *obj, fr *rule, fieldPath string, missing *[]string) (provided bool, err error) {
+	destVal, _ := destObj.field(fr.destName)
+
+	if !fr.nested {
+		if !r.opts.mask.covers(fieldPath) {
+			r.trace(fieldPath, ReasonMasked)
+			return true, skipJSON(dec)
+		}
+
+		t, err := jsonValueType(destVal.Type())
+		if err != nil {
+			return false, fmt.Errorf("failed to decode field %q: %w", fieldPath, err)
+		}
+		// decoded through a pointer, so null is recognized for value fields too
+		ref := reflect.New(reflect.PtrTo(t))
+		if err := dec.Decode(ref.Interface()); err != nil {
+			return false, fmt.Errorf("failed to decode field %q: %w", fieldPath, err)
+		}
+		return !ref.Elem().IsNil(), r.setRule(destObj, fr, ref.Elem(), fieldPath)
+	}
+
+	if !r.opts.mask.enters(fieldPath) {
+		r.trace(fieldPath, ReasonMasked)
+		return true, skipJSON(dec)
+	}
+
+	if !isNil(destVal) && fr.tag.noOverwrite {
+		// not applied, but its required fields are checked (like in From)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return false, fmt.Errorf("failed to decode field %q: %w", fieldPath, err)
+		}
+		if bytes.Equal(raw, []byte("null")) {
+			r.trace(fieldPath, ReasonNil)
+			return false, nil
+		}
+		if raw[0] != '{' {
+			return false, fmt.Errorf("failed to decode field %q: expected object, got %s", fieldPath, raw)
+		}
+		r.trace(fieldPath, ReasonNoOverwrite)
+		r.missingJSON(baseType(destVal.Type()), raw, fieldPath, missing)
+		return true, nil
+	}
+
+	t, err := dec.Token()
+	if err != nil {
+		return false, fmt.Errorf("failed to decode field %q: %w", fieldPath, err)
+	}
+	if t == nil {
+		r.trace(fieldPath, ReasonNil)
+		return false, nil
+	}
+	if t != json.Delim('{') {
+		return false, fmt.Errorf("failed to decode field %q: expected object, got %v", fieldPath, t)
+	}
+
+	destVal = r.derefDest(destVal, fieldPath)
+	r.allocDest(destVal, fieldPath)
+	r.trace(fieldPath, ReasonNested)
+
+	nested, err := newObj(refAny(destVal))
+	if err != nil {
+		return false, fmt.Errorf("invalid value of destination object: %w (on path: %q)", err, fieldPath)
+	}
+	return true, r.decodeObject(dec, nested, fieldPath, missing)
+}
+
+// jsonValueType returns the type the JSON value of field type t is decoded into. Optional
+// types not decoding themselves get their inner value, it is wrapped when set (like in From).
+func jsonValueType(t reflect.Type) (reflect.Type, error) {
+	if !isOptional(t) || implements(baseType(t), jsonUnmarshalerType) {
+		return t, nil
+	}
+	inner, ok := optionalInnerType(baseType(t))
+	if !ok {
+		return nil, fmt.Errorf("unknown value type of optional %s", baseType(t))
+	}
+	return inner, nil
+}
+
+// skipJSON reads the next value.
+func skipJSON(dec *json.Decoder) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); ok && (d == '{' || d == '[') {
+		return skipJSONRest(dec)
+	}
+	return nil
+}
+
+// skipJSONRest reads the rest of the object or array whose opening delimiter was read.
+func skipJSONRest(dec *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
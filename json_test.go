@@ -0,0 +1,193 @@
+package use
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type Address struct {
+		City   string  `usefrom:"city"`
+		Street *string `usefrom:"street"`
+	}
+
+	type Entity struct {
+		Name    string    `usefrom:"name"`
+		Email   *string   `usefrom:"email,nooverwrite"`
+		Age     int       `usefrom:"age"`
+		Tags    []string  `usefrom:"tags"`
+		At      time.Time `usefrom:"at"`
+		Code    string    `usefrom:"code,transform=trim|upper"`
+		Note    string    `usefrom:"note"`
+		Address *Address  `usefrom:"address"`
+		Billing *Address  `usefrom:"billing,nooverwrite"`
+		Secret  string
+	}
+
+	newObj := func() Entity {
+		return Entity{
+			Email:   asRef("old email"),
+			Note:    "old note",
+			Billing: &Address{City: "old billing"},
+		}
+	}
+
+	body := `{
+		"name": "John",
+		"email": "new email",
+		"AGE": 42,
+		"tags": ["a", "b"],
+		"at": "2024-05-01T10:00:00Z",
+		"code": " ab ",
+		"note": null,
+		"unknown": {"x": [1, {"y": 2}]},
+		"address": {"city": "Prague", "street": null, "other": 1},
+		"billing": {"city": "new billing"},
+		"Secret": "secret"
+	}`
+
+	dest := newObj()
+	var undo Undo
+	setFields, err := DecodeJSON(&dest, strings.NewReader(body), WithUndo(&undo))
+	require.NoError(t, err)
+	require.Equal(t, Entity{
+		Name:    "John",
+		Email:   asRef("old email"),
+		Age:     42,
+		Tags:    []string{"a", "b"},
+		At:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Code:    "AB",
+		Note:    "old note",
+		Address: &Address{City: "Prague"},
+		Billing: &Address{City: "old billing"},
+	}, dest)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Address.City", "Age", "At", "Code", "Name", "Tags"}, setFields)
+
+	undo()
+	require.Equal(t, newObj(), dest)
+
+	t.Run("same as From", func(t *testing.T) {
+		type Input struct {
+			Name  *string
+			Age   *int
+			Email *string
+		}
+		type Dest struct {
+			Name  string  `usefrom:""`
+			Age   int     `usefrom:""`
+			Email *string `usefrom:""`
+		}
+
+		d1, d2 := Dest{Age: 1}, Dest{Age: 1}
+		sf1, err := From(&d1, &Input{Name: asRef("n")})
+		require.NoError(t, err)
+		sf2, err := DecodeJSON(&d2, strings.NewReader(`{"Name": "n", "Email": null}`))
+		require.NoError(t, err)
+		require.Equal(t, sf1, sf2)
+		require.Equal(t, d1, d2)
+	})
+
+	t.Run("default and required", func(t *testing.T) {
+		type Inner struct {
+			ID   int    `usefrom:"id,required"`
+			Kind string `usefrom:"kind,default=basic"`
+		}
+		type Dest struct {
+			Name  string `usefrom:"name,required"`
+			Limit int    `usefrom:"limit,default=10"`
+			Inner *Inner `usefrom:"inner"`
+			Other *Inner `usefrom:"other"`
+		}
+
+		dest := Dest{}
+		_, err := DecodeJSON(&dest, strings.NewReader(`{"name": null, "inner": {"kind": "x"}}`))
+		var reqErr *RequiredError
+		require.True(t, errors.As(err, &reqErr))
+		require.Equal(t, []string{"Name", "Inner.ID"}, reqErr.Paths)
+		require.Equal(t, Dest{}, dest)
+
+		type Outer struct {
+			Inner *Inner `usefrom:"inner,nooverwrite"`
+		}
+		_, err = DecodeJSON(&Outer{Inner: &Inner{}}, strings.NewReader(`{"inner": {"kind": "x"}}`))
+		require.True(t, errors.As(err, &reqErr))
+		require.Equal(t, []string{"Inner.ID"}, reqErr.Paths) // like in From
+
+		setFields, err := DecodeJSON(&dest, strings.NewReader(`{"name": "n", "limit": null, "inner": {"id": 1}}`))
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name", "Inner.ID", "Inner.Kind", "Limit"}, setFields) // defaults are set at the end of the object
+		require.Equal(t, Dest{Name: "n", Limit: 10, Inner: &Inner{ID: 1, Kind: "basic"}}, dest)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		type Dest struct {
+			Name  sql.NullString     `usefrom:"name"`
+			Count testOption[int]    `usefrom:"count"`
+			Code  *sql.NullString    `usefrom:"code"`
+			Note  testOption[string] `usefrom:"note"`
+		}
+
+		dest := Dest{Note: some("old")}
+		setFields, err := DecodeJSON(&dest, strings.NewReader(`{"name": "x", "count": 42, "code": "c", "note": null}`))
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name", "Count", "Code"}, setFields)
+		require.Equal(t, Dest{
+			Name:  sql.NullString{String: "x", Valid: true},
+			Count: some(42),
+			Code:  &sql.NullString{String: "c", Valid: true},
+			Note:  some("old"),
+		}, dest)
+
+		type Unknown struct {
+			Value testMaybe[int] `usefrom:"value"`
+		}
+		_, err = DecodeJSON(&Unknown{}, strings.NewReader(`{"value": 1}`))
+		require.ErrorContains(t, err, "unknown value type of optional")
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		dest := Entity{}
+		setFields, err := DecodeJSON(&dest, strings.NewReader(body), Mask("Name", "Address.City"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"Name", "Address.City"}, setFields)
+
+		_, err = DecodeJSON(&dest, strings.NewReader(body), Mask("Nope"))
+		require.ErrorContains(t, err, `mask path "Nope" does not match any field`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := DecodeJSON(&Entity{}, strings.NewReader(`{"age": "x"}`))
+		require.ErrorContains(t, err, `failed to decode field "Age"`)
+
+		_, err = DecodeJSON(&Entity{}, strings.NewReader(`{"address": 1}`))
+		require.ErrorContains(t, err, `failed to decode field "Address"`)
+
+		_, err = DecodeJSON(&Entity{}, strings.NewReader(`[1]`))
+		require.Error(t, err)
+
+		_, err = DecodeJSON(&Entity{}, strings.NewReader(`{"name": "x"`))
+		require.Error(t, err)
+	})
+
+	t.Run("nothing is copied on error", func(t *testing.T) {
+		dest := newObj()
+		var undo Undo
+		var events []Event
+		setFields, err := DecodeJSON(&dest, strings.NewReader(`{"name": "John", "address": {"city": "Prague"}, "age": "x"}`),
+			WithUndo(&undo), WithTracer(TracerFunc(func(e Event) { events = append(events, e) })))
+		require.ErrorContains(t, err, `failed to decode field "Age"`)
+		require.Nil(t, setFields)
+		require.Equal(t, newObj(), dest)
+		require.Empty(t, events)
+
+		undo()
+		require.Equal(t, newObj(), dest)
+	})
+}
@@ -0,0 +1,267 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// timeLayouts are tried when a time is not in RFC 3339 format (e.g. the values of HTML date inputs).
+var timeLayouts = []string{"2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02"}
+
+// ParseError reports all values of FromForm, FromEnv or FromFlags that cannot be parsed
+// into the destination fields.
+type ParseError struct {
+	Values map[string]error // parse errors by the source name (form key, variable or flag name)
+}
+
+func (e *ParseError) Error() string {
+	names := make([]string, 0, len(e.Values))
+	for n := range e.Values {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, n := range names {
+		msgs[i] = fmt.Sprintf("%s: %v", n, e.Values[n])
+	}
+	return "invalid values: " + strings.Join(msgs, "; ")
+}
+
+// keySource provides the string values of a keyed source (like form values or environment).
+type keySource interface {
+	// key returns the key of the field source name, parent is the key of nested struct ("" for the root).
+	key(parent, name string) string
+	// get returns the values of the key, a slice field (slice is true) uses all of them.
+	get(key string, slice bool) []string
+	// mayHave reports if there may be a value of a field of the nested struct with the key.
+	mayHave(key string) bool
+}
+
+// keyedData are the parsed values of a keyed source by the destination field paths.
+type keyedData struct {
+	values  map[string]reflect.Value
+	nested  map[string]bool  // nested structs with at least one value
+	errs    map[string]error // parse errors by the keys
+	missing []string         // paths of required fields without value
+}
+
+// count is the number of the values, nested structs and errors (the fields with a value).
+func (kd *keyedData) count() int {
+	return len(kd.values) + len(kd.nested) + len(kd.errs)
+}
+
+// fromKeys applies src to dest. All values are parsed first, nothing is copied
+// when some of them are invalid or required fields are missing.
+func (r *run) fromKeys(dest any, src keySource) ([]string, error) {
+	defer r.setUndo()
+	defer r.setDefaults()
+
+	destObj, err := newObj(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of destination object: %w", err)
+	}
+	if err := r.checkMaskTypes(destObj.derefType(), nil); err != nil {
+		return nil, err
+	}
+
+	kd := &keyedData{
+		values: map[string]reflect.Value{},
+		nested: map[string]bool{},
+		errs:   map[string]error{},
+	}
+	if err := r.parseKeys(destObj.derefType(), src, "", "", kd, map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+	if len(kd.errs) > 0 {
+		return nil, &ParseError{Values: kd.errs}
+	}
+	if len(kd.missing) > 0 {
+		return nil, &RequiredError{Paths: kd.missing}
+	}
+
+	if err := r.applyKeys(destObj, kd, ""); err != nil {
+		return nil, err
+	}
+	return r.setFields, nil
+}
+
+// parseKeys parses the values of destT fields into kd, parent is the key of the nested struct.
+// The nested struct types being parsed are active, recursive types are not followed.
+func (r *run) parseKeys(destT reflect.Type, src keySource, parent, path string, kd *keyedData, active map[reflect.Type]bool) error {
+	p, err := r.m.plan(r.mode, destT, nil, r.opts)
+	if err != nil {
+		return fmt.Errorf("%w (on path: %q)", err, path)
+	}
+
+	active[destT] = true
+	defer delete(active, destT)
+
+	for _, fr := range p.rules {
+		sf, _ := destT.FieldByName(fr.destName)
+		key := src.key(parent, fr.srcName)
+		fieldPath := addToFields(path, fr.destName)
+
+		if fr.nested {
+			if !r.opts.mask.enters(fieldPath) {
+				continue
+			}
+			nt := baseType(sf.Type)
+			if !active[nt] && src.mayHave(key) {
+				n, m := kd.count(), len(kd.missing)
+				if err := r.parseKeys(nt, src, key, fieldPath, kd, active); err != nil {
+					return err
+				}
+				kd.nested[fieldPath] = kd.count() > n
+				if !kd.nested[fieldPath] {
+					kd.missing = kd.missing[:m] // fields of a struct without values are not required
+				}
+			}
+			if !kd.nested[fieldPath] && fr.tag.required {
+				kd.missing = append(kd.missing, fieldPath)
+			}
+			continue
+		}
+
+		if !r.opts.mask.covers(fieldPath) {
+			continue
+		}
+		var v reflect.Value
+		if vs := src.get(key, baseType(sf.Type).Kind() == reflect.Slice); len(vs) > 0 {
+			if v, err = parseValues(sf.Type, vs, r.mode == formMode); err != nil {
+				kd.errs[key] = err
+				continue
+			}
+		}
+		if v.IsValid() {
+			kd.values[fieldPath] = v
+		} else if fr.tag.required {
+			kd.missing = append(kd.missing, fieldPath)
+		}
+	}
+	return nil
+}
+
+// applyKeys sets the parsed values to destObj.
+func (r *run) applyKeys(destObj *obj, kd *keyedData, path string) error {
+	p, err := r.m.plan(r.mode, destObj.derefType(), nil, r.opts)
+	if err != nil {
+		return fmt.Errorf("%w (on path: %q)", err, path)
+	}
+
+	for _, fr := range p.rules {
+		fieldPath := addToFields(path, fr.destName)
+
+		if !fr.nested {
+			if !r.opts.mask.covers(fieldPath) {
+				r.trace(fieldPath, ReasonMasked)
+				continue
+			}
+			v, ok := kd.values[fieldPath]
+			if !ok && fr.tag.hasDefault {
+				err = r.setDefault(destObj, fr, fieldPath)
+			} else {
+				err = r.setRule(destObj, fr, v, fieldPath)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !r.opts.mask.enters(fieldPath) {
+			r.trace(fieldPath, ReasonMasked)
+			continue
+		}
+		if !kd.nested[fieldPath] {
+			r.trace(fieldPath, ReasonNil)
+			continue
+		}
+
+		destVal, _ := destObj.field(fr.destName)
+		if !isNil(destVal) && fr.tag.noOverwrite {
+			r.trace(fieldPath, ReasonNoOverwrite)
+			continue
+		}
+
+		destVal = r.derefDest(destVal, fieldPath)
+		r.allocDest(destVal, fieldPath)
+		r.trace(fieldPath, ReasonNested)
+
+		nested, err := newObj(refAny(destVal))
+		if err != nil {
+			return fmt.Errorf("invalid value of destination object: %w (on path: %q)", err, fieldPath)
+		}
+		if err := r.applyKeys(nested, kd, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isKeyNested reports if the fields of a struct are set from their own keys.
+// Types parsed from text (like time.Time) and structs without exported fields are set as values.
+func isKeyNested(t reflect.Type) bool {
+	return containsStructOrPtrToStruct(t) && hasExportedFields(t) && !implements(baseType(t), textUnmarshalerType)
+}
+
+// parseValues parses the values of one key into the field type t. The slices get all values
+// (except empty ones of non-string elements), other types the first value.
+// The returned value is invalid when there is nothing to set. With checkbox "on" is true
+// (the value of checked HTML checkbox without value attribute).
+func parseValues(t reflect.Type, vs []string, checkbox bool) (reflect.Value, error) {
+	bt := baseType(t)
+	if bt.Kind() != reflect.Slice || implements(bt, textUnmarshalerType) {
+		return parseValue(bt, vs[0], checkbox)
+	}
+
+	sv := reflect.MakeSlice(bt, 0, len(vs))
+	for i, s := range vs {
+		ev, err := parseValue(bt.Elem(), s, checkbox)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("value %d: %w", i, err)
+		}
+		if !ev.IsValid() {
+			continue
+		}
+		e := reflect.New(bt.Elem()).Elem()
+		if err := assign(e, ev); err != nil {
+			return reflect.Value{}, fmt.Errorf("value %d: %w", i, err)
+		}
+		sv = reflect.Append(sv, e)
+	}
+	return sv, nil
+}
+
+// parseValue parses s into (dereferenced) type t. Optional types get their inner value,
+// the empty string of non-string type is nothing to set.
+func parseValue(t reflect.Type, s string, checkbox bool) (reflect.Value, error) {
+	t = baseType(t)
+	switch {
+	case isOptional(t):
+		inner, ok := optionalInnerType(t)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown value type of optional %s", t)
+		}
+		return parseValue(inner, s, checkbox) // wrapped when set
+	case s == "" && t.Kind() != reflect.String:
+		return reflect.Value{}, nil
+	case checkbox && s == "on" && t.Kind() == reflect.Bool:
+		s = "true"
+	}
+
+	v, err := parseString(t, s)
+	if err != nil && t == timeType {
+		for _, layout := range timeLayouts {
+			if tm, terr := time.Parse(layout, s); terr == nil {
+				return reflect.ValueOf(&tm).Elem(), nil
+			}
+		}
+	}
+	return v, err
+}
@@ -0,0 +1,195 @@
+package use
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Config defines the conventions of a Mapper.
+type Config struct {
+	FromTag string // tag on destination struct used by From, default "usefrom"
+	InTag   string // tag on source struct used by In, default "usein"
+
+	// DefaultNoOverwrite makes every field behave as tagged with `nooverwrite`.
+	// A single field can opt out with `overwrite` tag option.
+	DefaultNoOverwrite bool
+	// DefaultOmitMissing makes every field behave as tagged with `omitmissing`.
+	// A single field can opt out with `mustexist` tag option.
+	DefaultOmitMissing bool
+
+	// Options are applied to every call before the options of the call.
+	Options []Option
+}
+
+// Mapper copies values between structs with its own configuration.
+// Each Mapper has its own cache of compiled plans and its own converters,
+// so different parts of a program can use different conventions.
+// Mapper is safe for concurrent use.
+//
+// The package level functions (From, In, Auto, ...) use a default Mapper
+// with zero Config.
+type Mapper struct {
+	fromTag  tagKind
+	inTag    tagKind
+	defaults tag
+	opts     []Option
+
+	plans sync.Map // planKey -> *planEntry
+	conv  converters
+	tr    transforms
+	maps  mappings
+}
+
+var defaultMapper = NewMapper(Config{})
+
+// NewMapper returns a new Mapper with the given configuration.
+func NewMapper(cfg Config) *Mapper {
+	m := &Mapper{
+		fromTag: fromTag,
+		inTag:   inTag,
+		defaults: tag{
+			noOverwrite: cfg.DefaultNoOverwrite,
+			omitMissing: cfg.DefaultOmitMissing,
+		},
+		opts: cfg.Options,
+	}
+	if cfg.FromTag != "" {
+		m.fromTag = tagKind(cfg.FromTag)
+	}
+	if cfg.InTag != "" {
+		m.inTag = tagKind(cfg.InTag)
+	}
+	return m
+}
+
+// From is like the package level From, using the mapper configuration.
+func (m *Mapper) From(dest, src any, opts ...Option) (setFields []string, err error) {
+	return m.newRun(fromMode, opts).apply(dest, src)
+}
+
+// In is like the package level In, using the mapper configuration.
+func (m *Mapper) In(dest, src any, opts ...Option) (setFields []string, err error) {
+	return m.newRun(inMode, opts).apply(dest, src)
+}
+
+// Auto is like the package level Auto, using the mapper configuration.
+func (m *Mapper) Auto(dest, src any, opts ...Option) (setFields []string, err error) {
+	return m.newRun(autoMode, opts).apply(dest, src)
+}
+
+// Reverse is like the package level Reverse, using the mapper configuration.
+func (m *Mapper) Reverse(dest, src any, opts ...Option) (setFields []string, err error) {
+	return m.newRun(reverseMode, opts).apply(dest, src)
+}
+
+// AutoPlan is like the package level AutoPlan, using the mapper configuration.
+func (m *Mapper) AutoPlan(dest, src any, opts ...Option) (*Plan, error) {
+	return m.autoPlan(dest, src, opts)
+}
+
+// RegisterConverter registers a function converting values of one type to another.
+// The function must have a form func(S) D or func(S) (D, error), where neither S nor D is a pointer.
+// It is used when source and destination field types do not match (S and D
+// are compared without pointers, so it works for *S and *D fields too).
+//
+// Registering a converter for a struct destination type makes such fields be set
+// by the converter instead of being copied recursively.
+func (m *Mapper) RegisterConverter(fn any) error {
+	if err := m.conv.register(fn); err != nil {
+		return err
+	}
+
+	// plans depend on the converters (recursive vs converted fields)
+	m.clearPlans()
+	return nil
+}
+
+// RegisterTransform registers a named transform used by `transform=name1|name2` tag option.
+// The function must have a form func(T) T or func(T) (T, error), where T is not a pointer.
+// The transforms run in order on the copied value (after conversion) before it is set
+// to the destination field, so `nooverwrite` and the tracked changes see the final value.
+// More functions of different types can be registered under one name.
+//
+// Built-in transforms are "trim", "lower", "upper" (strings) and "abs" (signed numbers),
+// a registered function takes precedence for its type.
+//
+// Example:
+//
+//	err := m.RegisterTransform("percent", func(n int) int {
+//		if n > 100 {
+//			return 100
+//		}
+//		return n
+//	})
+//	// Discount int `usefrom:",transform=abs|percent"`
+func (m *Mapper) RegisterTransform(name string, fn any) error {
+	if err := m.tr.register(name, fn); err != nil {
+		return err
+	}
+
+	// plans are validated against the transforms
+	m.clearPlans()
+	return nil
+}
+
+// RegisterTransform registers a transform on the default Mapper. See Mapper.RegisterTransform.
+func RegisterTransform(name string, fn any) error {
+	return defaultMapper.RegisterTransform(name, fn)
+}
+
+// Register registers a mapping function for fields which cannot be expressed by tags
+// (e.g. FullName built from First and Last). The function must have a form
+// func(d *Dest, s *Src, rec use.Recorder) error. It runs after the tag rules whenever
+// Dest is copied from Src, including nested structs, and sets the fields by rec,
+// so the changes are part of the returned setFields (and undo, tracked changes, ...).
+//
+// Example:
+//
+//	err := m.Register(func(d *User, s *UserInput, rec use.Recorder) error {
+//		if s.First == nil || s.Last == nil {
+//			return nil
+//		}
+//		return rec.Set("FullName", *s.First+" "+*s.Last)
+//	})
+func (m *Mapper) Register(fn any) error {
+	return m.maps.register(fn)
+}
+
+// Register registers a mapping function on the default Mapper. See Mapper.Register.
+func Register(fn any) error {
+	return defaultMapper.Register(fn)
+}
+
+func (m *Mapper) clearPlans() {
+	m.plans.Range(func(k, _ any) bool {
+		m.plans.Delete(k)
+		return true
+	})
+}
+
+// RegisterConverter registers a converter on the default Mapper. See Mapper.RegisterConverter.
+func RegisterConverter(fn any) error {
+	return defaultMapper.RegisterConverter(fn)
+}
+
+func (m *Mapper) newRun(md mode, opts []Option) *run {
+	all := make([]Option, 0, len(m.opts)+len(opts))
+	all = append(all, m.opts...)
+	all = append(all, opts...)
+	return &run{m: m, mode: md, opts: newOptions(all)}
+}
+
+// isNested reports if the field should be copied recursively (and not set as a value).
+// Optional source (like sql.NullTime) is always unwrapped and set as a value,
+// non struct source (like string parsed into time.Time) is converted and structs without
+// exported fields (like time.Time) are copied as values.
+// Arrays of the same length with nested elements are copied element-wise.
+func (m *Mapper) isNested(destT, srcT reflect.Type) bool {
+	if destT.Kind() == reflect.Array && srcT.Kind() == reflect.Array {
+		return destT.Len() == srcT.Len() && m.isNested(destT.Elem(), srcT.Elem())
+	}
+	if !containsStructOrPtrToStruct(destT) || !containsStructOrPtrToStruct(srcT) || !hasExportedFields(destT) {
+		return false
+	}
+	return !m.conv.has(baseType(srcT), baseType(destT))
+}
@@ -0,0 +1,125 @@
+package use
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapperCustomTags(t *testing.T) {
+	m := NewMapper(Config{FromTag: "patch", InTag: "apply"})
+
+	type T struct {
+		F1 string  `patch:""`
+		F2 *int    `patch:"F2inp"`
+		F3 string  `usefrom:""` // not the mapper tag
+		F4 float64 `apply:""`   // apply is used on source only
+	}
+
+	type TInput struct {
+		F1    *string `apply:"F4"`
+		F2inp int
+		F3    string
+		F4    float64
+	}
+
+	obj := T{F3: "old f3"}
+	setFields, err := m.From(&obj, &TInput{F1: asRef("new f1"), F2inp: 42, F3: "new f3"})
+	require.NoError(t, err)
+	require.Equal(t, T{F1: "new f1", F2: asRef(42), F3: "old f3"}, obj)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F1", "F2"}, setFields)
+
+	type TInputIn struct {
+		F1 *string `apply:""`
+		F5 float64 `apply:"F4"`
+		F6 string  `usein:"F3"` // not the mapper tag
+	}
+
+	obj = T{F3: "old f3"}
+	setFields, err = m.In(&obj, &TInputIn{F1: asRef("new f1"), F5: 1.5, F6: "new f3"})
+	require.NoError(t, err)
+	require.Equal(t, T{F1: "new f1", F3: "old f3", F4: 1.5}, obj)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F1", "F4"}, setFields)
+}
+
+func TestMapperDefaults(t *testing.T) {
+	m := NewMapper(Config{DefaultNoOverwrite: true, DefaultOmitMissing: true})
+
+	type T struct {
+		F1 *string `usefrom:""`
+		F2 *string `usefrom:",overwrite"`
+		F3 *string `usefrom:""`
+		F4 *string `usefrom:""` // missing in source
+	}
+
+	type TInput struct {
+		F1 *string
+		F2 *string
+		F3 *string
+	}
+
+	obj := T{F1: asRef("old f1"), F2: asRef("old f2")}
+	setFields, err := m.From(&obj, &TInput{F1: asRef("new f1"), F2: asRef("new f2"), F3: asRef("new f3")})
+	require.NoError(t, err)
+	require.Equal(t, T{F1: asRef("old f1"), F2: asRef("new f2"), F3: asRef("new f3")}, obj)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F2", "F3"}, setFields)
+
+	type TStrict struct {
+		F4 *string `usefrom:",mustexist"`
+	}
+
+	_, err = m.From(&TStrict{}, &TInput{})
+	require.Error(t, err)
+
+	// the default mapper is not affected
+	_, err = From(&obj, &TInput{})
+	require.Error(t, err)
+}
+
+func TestMapperConverter(t *testing.T) {
+	type T struct {
+		Count   int       `usefrom:""`
+		Created time.Time `usefrom:""`
+		Ptr     *int      `usefrom:""`
+	}
+
+	type TInput struct {
+		Count   *string
+		Created string
+		Ptr     string
+	}
+
+	m := NewMapper(Config{})
+	require.NoError(t, m.RegisterConverter(strconv.Atoi))
+	require.NoError(t, m.RegisterConverter(func(s string) (time.Time, error) {
+		return time.Parse(time.RFC3339, s)
+	}))
+
+	obj := T{}
+	_, err := m.From(&obj, &TInput{Count: asRef("42"), Created: "2024-01-02T03:04:05Z", Ptr: "7"})
+	require.NoError(t, err)
+	require.Equal(t, T{Count: 42, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Ptr: asRef(7)}, obj)
+
+	_, err = m.From(&obj, &TInput{Count: asRef("nan"), Created: "2024-01-02T03:04:05Z"})
+	require.Error(t, err)
+
+	// other mappers do not see the converters
+	_, err = NewMapper(Config{}).From(&obj, &TInput{Count: asRef("42"), Created: "2024-01-02T03:04:05Z", Ptr: "7"})
+	require.Error(t, err)
+}
+
+func TestMapperConverterInvalid(t *testing.T) {
+	m := NewMapper(Config{})
+	require.Error(t, m.RegisterConverter(42))
+	require.Error(t, m.RegisterConverter(func(s string) {}))
+	require.Error(t, m.RegisterConverter(func(s string) (int, int) { return 0, 0 }))
+	require.Error(t, m.RegisterConverter(func(s *string) int { return 0 }))
+	require.NoError(t, m.RegisterConverter(func(s string) (int, error) { return 0, errors.New("never") }))
+}
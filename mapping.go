@@ -0,0 +1,104 @@
+package use
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Recorder sets destination fields from a mapping function registered with Register.
+// The changes are merged into the result of the copy call (setFields, undo and tracked changes).
+type Recorder interface {
+	// Set sets the field of the destination struct (by name) to value, with the same
+	// rules as a field copied by tags (nil value is not set, types are converted).
+	Set(field string, value any) error
+}
+
+var recorderType = reflect.TypeOf((*Recorder)(nil)).Elem()
+
+// mappings is a registry of mapping functions per pair of destination and source types.
+type mappings struct {
+	mu  sync.RWMutex
+	fns map[convKey]reflect.Value
+}
+
+func (ms *mappings) register(fn any) error {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return errors.New("mapping must be a function")
+	}
+
+	ft := fv.Type()
+	if ft.NumIn() != 3 || ft.NumOut() != 1 || ft.Out(0) != errorType || ft.In(2) != recorderType ||
+		!isStructRef(ft.In(0)) || !isStructRef(ft.In(1)) {
+		return fmt.Errorf("mapping %s must be func(*Dest, *Src, use.Recorder) error", ft)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.fns == nil {
+		ms.fns = make(map[convKey]reflect.Value)
+	}
+	ms.fns[convKey{src: ft.In(1).Elem(), dest: ft.In(0).Elem()}] = fv
+	return nil
+}
+
+func (ms *mappings) lookup(destT, srcT reflect.Type) (reflect.Value, bool) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	fv, ok := ms.fns[convKey{src: srcT, dest: destT}]
+	return fv, ok
+}
+
+func isStructRef(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
+
+// recorder implements Recorder for one destination struct of the run.
+type recorder struct {
+	r       *run
+	destObj *obj
+	path    string
+}
+
+func (rec *recorder) Set(field string, value any) error {
+	fieldPath := addToFields(rec.path, field)
+	destVal, ok := rec.destObj.field(field)
+	if !ok {
+		return fmt.Errorf("field %q does not exist", fieldPath)
+	}
+	if !rec.r.opts.mask.covers(fieldPath) {
+		rec.r.trace(fieldPath, ReasonMasked)
+		return nil
+	}
+
+	old := rec.r.snapshot(destVal)
+	wasSet, unchanged, err := rec.r.setField(rec.destObj, field, reflect.ValueOf(value), &tag{fieldName: field})
+	if err != nil {
+		return fmt.Errorf("failed to set field %q: %w", fieldPath, err)
+	}
+	if wasSet {
+		rec.r.changed(fieldPath, destVal, old, false)
+		rec.r.setFields = append(rec.r.setFields, fieldPath)
+	}
+	if rec.r.opts.tracer != nil {
+		rec.r.trace(fieldPath, setReason(reflect.ValueOf(value), wasSet, unchanged))
+	}
+	return nil
+}
+
+// runMapping calls the mapping function registered for the types of destObj and srcObj (if any).
+func (r *run) runMapping(destObj, srcObj *obj, path string) error {
+	fn, ok := r.m.maps.lookup(destObj.derefType(), srcObj.derefType())
+	if !ok {
+		return nil
+	}
+
+	rec := &recorder{r: r, destObj: destObj, path: path}
+	out := fn.Call([]reflect.Value{destObj.v, srcObj.v, reflect.ValueOf(rec)})
+	if err, _ := out[0].Interface().(error); err != nil {
+		return fmt.Errorf("mapping %s from %s: %w (on path: %q)", destObj.derefType(), srcObj.derefType(), err, path)
+	}
+	return nil
+}
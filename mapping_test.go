@@ -0,0 +1,105 @@
+package use
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	type Contact struct {
+		Email  string `usefrom:""`
+		Status string
+	}
+
+	type User struct {
+		ID       int      `usefrom:""`
+		FullName string   // set by mapping
+		Contact  *Contact `usefrom:""`
+	}
+
+	type ContactInput struct {
+		Email     string
+		Verified  bool
+		Suspended bool
+	}
+
+	type UserInput struct {
+		ID      int
+		First   *string
+		Last    *string
+		Contact *ContactInput
+	}
+
+	m := NewMapper(Config{})
+	require.NoError(t, m.Register(func(d *User, s *UserInput, rec Recorder) error {
+		if s.First == nil || s.Last == nil {
+			return nil
+		}
+		return rec.Set("FullName", *s.First+" "+*s.Last)
+	}))
+	require.NoError(t, m.Register(func(d *Contact, s *ContactInput, rec Recorder) error {
+		switch {
+		case s.Suspended:
+			return rec.Set("Status", "suspended")
+		case s.Verified:
+			return rec.Set("Status", "active")
+		}
+		return errors.New("contact is not verified")
+	}))
+
+	src := UserInput{
+		ID:      1,
+		First:   asRef("John"),
+		Last:    asRef("Doe"),
+		Contact: &ContactInput{Email: "john@example.com", Verified: true},
+	}
+
+	dest := User{FullName: "old"}
+	var undo Undo
+	setFields, err := m.From(&dest, &src, WithUndo(&undo))
+	require.NoError(t, err)
+	require.Equal(t, User{
+		ID:       1,
+		FullName: "John Doe",
+		Contact:  &Contact{Email: "john@example.com", Status: "active"},
+	}, dest)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Contact.Email", "Contact.Status", "FullName", "ID"}, setFields)
+
+	undo()
+	require.Equal(t, User{FullName: "old"}, dest)
+
+	t.Run("mask", func(t *testing.T) {
+		dest := User{}
+		setFields, err := m.From(&dest, &src, Mask("ID"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"ID"}, setFields)
+		require.Equal(t, User{ID: 1}, dest)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		dest := User{}
+		_, err := m.From(&dest, &UserInput{Contact: &ContactInput{}})
+		require.ErrorContains(t, err, "contact is not verified")
+		require.ErrorContains(t, err, `(on path: "Contact")`)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		m := NewMapper(Config{})
+		require.NoError(t, m.Register(func(d *User, s *UserInput, rec Recorder) error {
+			return rec.Set("Name", "x")
+		}))
+		_, err := m.From(&User{}, &src)
+		require.ErrorContains(t, err, `field "Name" does not exist`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require.Error(t, m.Register(nil))
+		require.Error(t, m.Register(func(d User, s *UserInput, rec Recorder) error { return nil }))
+		require.Error(t, m.Register(func(d *User, s *UserInput) error { return nil }))
+		require.Error(t, m.Register(func(d *User, s *UserInput, rec Recorder) {}))
+	})
+}
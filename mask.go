@@ -0,0 +1,167 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Mask restricts the copy to the fields on given paths (in the same format as setFields,
+// e.g. "Name", "Address.City"). A path of a nested struct field applies to the whole
+// subtree, it can be written with a wildcard too ("Address.*"), "*" matches everything.
+// Fields not matching the mask are left untouched (nested structs are not even allocated).
+//
+// It is an error when a path does not match any field of the copy rules.
+//
+// Example (gRPC style update mask):
+//
+//	setFields, err := use.From(&entity, &input, use.Mask(req.UpdateMask.Paths...))
+func Mask(paths ...string) Option {
+	return func(o *options) {
+		if o.mask == nil {
+			o.mask = &mask{}
+		}
+		for _, p := range paths {
+			if p == "*" {
+				o.mask.all = true
+				continue
+			}
+			o.mask.paths = append(o.mask.paths, strings.TrimSuffix(p, ".*"))
+		}
+	}
+}
+
+// mask holds the paths without wildcards, all is set by "*".
+type mask struct {
+	paths []string
+	all   bool
+}
+
+// covers reports if the field on path is selected by the mask (directly or by its parent).
+func (mk *mask) covers(path string) bool {
+	if mk == nil || mk.all {
+		return true
+	}
+	for _, p := range mk.paths {
+		if p == path || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// enters reports if some field within the nested struct on path is selected by the mask.
+func (mk *mask) enters(path string) bool {
+	if mk.covers(path) {
+		return true
+	}
+	for _, p := range mk.paths {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMask verifies every mask path matches a copied field.
+func (r *run) checkMask(dest, src any) error {
+	if r.opts.mask == nil {
+		return nil
+	}
+
+	destT, err := structType(dest)
+	if err != nil {
+		return nil // reported by copy
+	}
+	srcT, err := structType(src)
+	if err != nil {
+		return nil // reported by copy
+	}
+	return r.checkMaskTypes(destT, srcT)
+}
+
+// checkMaskTypes verifies every mask path matches a field of the plans of destT and srcT
+// (nil for keyed sources like JSON, the plans have no source types).
+func (r *run) checkMaskTypes(destT, srcT reflect.Type) error {
+	if r.opts.mask == nil {
+		return nil
+	}
+
+	for _, p := range r.opts.mask.paths {
+		ok := false
+		if p != "" {
+			var err error
+			if ok, err = r.maskPathExists(destT, srcT, p); err != nil {
+				return err
+			}
+		}
+		if !ok {
+			return fmt.Errorf("mask path %q does not match any field", p)
+		}
+	}
+	return nil
+}
+
+func (r *run) maskPathExists(destT, srcT reflect.Type, path string) (bool, error) {
+	segs := strings.Split(path, ".")
+	for i, seg := range segs {
+		seg, idx, hasIdx := splitIndex(seg)
+		p, err := r.m.plan(r.mode, destT, srcT, r.opts)
+		if err != nil {
+			return false, fmt.Errorf("%w (on path: %q)", err, strings.Join(segs[:i], "."))
+		}
+
+		var found *rule
+		for _, fr := range p.rules {
+			if fr.destName == seg && !fr.missing {
+				found = fr
+				break
+			}
+		}
+		if found == nil {
+			return false, nil
+		}
+		dsf, _ := destT.FieldByName(found.destName)
+		dt, st := dsf.Type, reflect.Type(nil)
+		if srcT != nil {
+			ssf, _ := srcT.FieldByName(found.srcName)
+			st = ssf.Type
+		}
+		if hasIdx {
+			if !found.nested || dt.Kind() != reflect.Array || idx >= dt.Len() {
+				return false, nil
+			}
+			dt, st = dt.Elem(), st.Elem()
+		}
+		if i == len(segs)-1 {
+			return true, nil
+		}
+		if !found.nested || dt.Kind() == reflect.Array {
+			return false, nil
+		}
+
+		destT = baseType(dt)
+		if srcT == nil {
+			continue
+		}
+		srcT = baseType(st)
+		if srcT.Kind() != reflect.Struct {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// splitIndex splits the path segment of array element ("Addresses[1]") to the field name and index.
+func splitIndex(seg string) (name string, idx int, ok bool) {
+	i := strings.IndexByte(seg, '[')
+	if i < 0 || !strings.HasSuffix(seg, "]") {
+		return seg, 0, false
+	}
+	idx, err := strconv.Atoi(seg[i+1 : len(seg)-1])
+	if err != nil || idx < 0 {
+		return seg, 0, false
+	}
+	return seg[:i], idx, true
+}
@@ -0,0 +1,93 @@
+package use
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMask(t *testing.T) {
+	type Address struct {
+		City   string `usefrom:""`
+		Street string `usefrom:""`
+	}
+
+	type T struct {
+		Name    string   `usefrom:""`
+		Email   string   `usefrom:""`
+		Address *Address `usefrom:""`
+		Billing *Address `usefrom:""`
+	}
+
+	src := T{
+		Name:    "new name",
+		Email:   "new email",
+		Address: &Address{City: "new city", Street: "new street"},
+		Billing: &Address{City: "new billing city", Street: "new billing street"},
+	}
+
+	newObj := func() T {
+		return T{Name: "old name", Email: "old email"}
+	}
+
+	for name, tc := range map[string]struct {
+		mask      []string
+		expected  T
+		setFields []string
+	}{
+		"fields": {
+			mask:      []string{"Name", "Address.City"},
+			expected:  T{Name: "new name", Email: "old email", Address: &Address{City: "new city"}},
+			setFields: []string{"Address.City", "Name"},
+		},
+		"wildcard subtree": {
+			mask:      []string{"Billing.*"},
+			expected:  T{Name: "old name", Email: "old email", Billing: &Address{City: "new billing city", Street: "new billing street"}},
+			setFields: []string{"Billing.City", "Billing.Street"},
+		},
+		"nested field subtree": {
+			mask:      []string{"Email", "Billing"},
+			expected:  T{Name: "old name", Email: "new email", Billing: &Address{City: "new billing city", Street: "new billing street"}},
+			setFields: []string{"Billing.City", "Billing.Street", "Email"},
+		},
+		"everything": {
+			mask:      []string{"*"},
+			expected:  src,
+			setFields: []string{"Address.City", "Address.Street", "Billing.City", "Billing.Street", "Email", "Name"},
+		},
+		"nothing": {
+			mask:     []string{},
+			expected: newObj(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			obj := newObj()
+			setFields, err := From(&obj, &src, Mask(tc.mask...))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, obj)
+
+			sort.Strings(setFields)
+			require.Equal(t, tc.setFields, setFields)
+		})
+	}
+}
+
+func TestMaskErrors(t *testing.T) {
+	type Address struct {
+		City string `usefrom:""`
+	}
+
+	type T struct {
+		Name     string   `usefrom:""`
+		Untagged string   // not copied
+		Address  *Address `usefrom:""`
+	}
+
+	obj := T{Name: "old name"}
+	for _, path := range []string{"Unknown", "Untagged", "Name.Sub", "Address.Street", "", ".", ".*", "Name*"} {
+		_, err := From(&obj, &T{Name: "new name"}, Mask("Name", path))
+		require.ErrorContains(t, err, "does not match any field", path)
+		require.Equal(t, "old name", obj.Name, "nothing is copied")
+	}
+}
@@ -8,17 +8,19 @@ import (
 )
 
 type obj struct {
-	t                reflect.Type
-	isIndirect       bool
-	fromTaggedFields map[string]*taggedField // key is field name
-	inTaggedFields   map[string]*taggedField // key is field name
-	v                reflect.Value
+	t          reflect.Type
+	isIndirect bool
+	v          reflect.Value
 }
 
 func newObj(ov any) (*obj, error) {
 	t := reflect.TypeOf(ov)
 	v := reflect.ValueOf(ov)
 
+	if t == nil {
+		return nil, errors.New("obj must be a reference to struct")
+	}
+
 	if indirect, derefed := derefType(t); !indirect || derefed.Kind() != reflect.Struct {
 		return nil, errors.New("obj must be a reference to struct")
 	}
@@ -33,57 +35,9 @@ func newObj(ov any) (*obj, error) {
 		v:          v,
 	}
 
-	derefedt := o.derefType()
-	derefedv := o.derefValue()
-
-	for i := 0; i < derefedt.NumField(); i++ {
-		tagfrom := parseTag(derefedt.Field(i), fromTag)
-		if tagfrom != nil {
-			vf := derefedv.Field(i)
-			if !vf.CanSet() {
-				return nil, fmt.Errorf("field %q is not settable", derefedt.Field(i).Name)
-			}
-
-			if o.fromTaggedFields == nil {
-				o.fromTaggedFields = make(map[string]*taggedField, 0)
-			}
-			o.fromTaggedFields[derefedt.Field(i).Name] = &taggedField{
-				sf:         derefedt.Field(i),
-				isIndirect: derefedt.Field(i).Type.Kind() == reflect.Ptr,
-				tag:        tagfrom,
-			}
-		}
-
-		tagin := parseTag(derefedt.Field(i), inTag)
-		if tagin != nil {
-			if o.inTaggedFields == nil {
-				o.inTaggedFields = make(map[string]*taggedField, 0)
-			}
-			o.inTaggedFields[derefedt.Field(i).Name] = &taggedField{
-				sf:         derefedt.Field(i),
-				isIndirect: derefedt.Field(i).Type.Kind() == reflect.Ptr,
-				tag:        tagin,
-			}
-		}
-	}
-
 	return o, nil
 }
 
-func (o *obj) tag(tagk tagKind, fname string) *tag {
-	if tagk == fromTag {
-		if tf, ok := o.fromTaggedFields[fname]; ok {
-			return tf.tag
-		}
-		return nil
-	}
-
-	if tf, ok := o.inTaggedFields[fname]; ok {
-		return tf.tag
-	}
-	return nil
-}
-
 func (o *obj) derefType() reflect.Type {
 	if o.isIndirect {
 		return o.t.Elem()
@@ -103,37 +57,10 @@ func (o *obj) field(fname string) (reflect.Value, bool) {
 	return fv, true
 }
 
-func (o *obj) fieldType(fname string) (reflect.Type, bool) {
-	sf, ok := o.derefType().FieldByName(fname)
-	if !ok {
-		return nil, false
-	}
-	return sf.Type, true
-}
-
-func (o *obj) fieldRefAny(fname string) (v any, exists bool, isNilV bool, indirect bool) {
-	var fv reflect.Value
-	fv, exists = o.field(fname)
-	if !exists {
-		return
-	}
-
-	isNilV = isNil(fv)
-	if isNilV {
-		return
-	}
-
-	indirect, _ = derefValue(fv)
-	if indirect {
-		v = fv.Interface()
-		return
-	}
-	v = fv.Addr().Interface()
-	return
-}
-
-// setField does NOT set field if source is nil
-func (o *obj) setField(fname string, v reflect.Value, tg *tag) (wasSet bool, err error) {
+// setField does NOT set field if source is nil. When types do not match, conv is used
+// to convert the value (conv may be nil). The transforms of the tag run on the final value
+// before it is assigned. Pointers of any depth are resolved on both sides.
+func (o *obj) setField(fname string, v reflect.Value, tg *tag, conv *converters, tr *transforms) (wasSet bool, err error) {
 	if isNil(v) {
 		return false, nil
 	}
@@ -150,62 +77,100 @@ func (o *obj) setField(fname string, v reflect.Value, tg *tag) (wasSet bool, err
 		return false, errors.New("dest field not settable")
 	}
 
+	return setValue(fv, v, tg, conv, tr)
+}
+
+// setValue sets non nil v to settable fv, see setField.
+func setValue(fv, v reflect.Value, tg *tag, conv *converters, tr *transforms) (wasSet bool, err error) {
 	if tg.noOverwrite && !isNil(fv) {
 		return false, nil
 	}
 
+	// "present but null" source (like **string with nil inner pointer) clears the destination
+	if isNullRef(v) {
+		clearValue(fv)
+		return true, nil
+	}
+
 	if !typesMatch(fv.Type(), v.Type()) {
-		return false, fmt.Errorf("types not assignable. dest %q, src %q", fv.Type().Kind(), v.Type().Kind())
+		// optional source is unwrapped (the missing value was handled as nil above)
+		if inner, _, ok := unwrapOptional(v); ok {
+			v = inner
+		}
 	}
 
-	if fv.Kind() == reflect.Ptr {
-		if v.Kind() == reflect.Ptr {
-			fv.Set(v)
-			return true, nil
+	if isOptional(fv.Type()) && !typesMatch(fv.Type(), v.Type()) {
+		if len(tg.transforms) > 0 {
+			if v, err = tr.apply(tg.transforms, v); err != nil {
+				return false, err
+			}
 		}
-		if !v.CanAddr() {
-			return false, errors.New("cannot take address of source value")
+		if err := setOptional(fv, v, conv); err != nil {
+			return false, err
 		}
-		fv.Set(v.Addr())
 		return true, nil
 	}
 
-	if v.Kind() == reflect.Ptr {
-		fv.Set(v.Elem())
-		return true, nil
+	if !typesMatch(fv.Type(), v.Type()) {
+		cv, ok, err := conv.convert(v, fv.Type())
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, fmt.Errorf("types not assignable. dest %q, src %q", fv.Type().Kind(), v.Type().Kind())
+		}
+		v = cv
 	}
 
-	fv.Set(v)
+	if len(tg.transforms) > 0 {
+		if v, err = tr.apply(tg.transforms, v); err != nil {
+			return false, err
+		}
+	}
 
+	if err := assign(fv, v); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
-func (o *obj) createEmpty(fieldname string) error {
-	ft, ok := o.derefType().FieldByName(fieldname)
-	if !ok {
-		return fmt.Errorf("%q is not defined on struct", fieldname)
+// assign sets non nil v of matching type to fv, resolving the pointer levels on both sides.
+// A reference v is shared with fv, missing pointer levels of fv are allocated.
+func assign(fv, v reflect.Value) error {
+	var ptr reflect.Value // the last pointer to the value
+	for v.Kind() == reflect.Ptr {
+		ptr = v
+		v = v.Elem()
 	}
 
-	_, derefedT := derefType(ft.Type)
-
-	tv := reflect.New(derefedT)
+	if fv.Kind() != reflect.Ptr {
+		fv.Set(v)
+		return nil
+	}
 
-	tg := o.tag(fromTag, fieldname)
-	if tg == nil {
-		return fmt.Errorf("tag for field %q does not exist", fieldname) // should not happen
+	if !ptr.IsValid() {
+		if !v.CanAddr() {
+			return errors.New("cannot take address of source value")
+		}
+		ptr = v.Addr()
 	}
-	_, err := o.setField(fieldname, tv, tg)
-	if err != nil {
-		return fmt.Errorf("error initializing new empty struct value: %w", err)
+	for ptr.Type() != fv.Type() {
+		np := reflect.New(ptr.Type())
+		np.Elem().Set(ptr)
+		ptr = np
 	}
-
+	fv.Set(ptr)
 	return nil
 }
 
-type taggedField struct {
-	sf         reflect.StructField // has Name, Type, Tag
-	isIndirect bool
-	tag        *tag
+// clearValue sets fv to null: a multi-level pointer gets non nil outer and nil inner pointer,
+// a pointer is set to nil and a value to zero value.
+func clearValue(fv reflect.Value) {
+	if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Ptr {
+		fv.Set(reflect.New(fv.Type().Elem()))
+		return
+	}
+	fv.Set(reflect.Zero(fv.Type()))
 }
 
 type tagKind string
@@ -216,18 +181,29 @@ const (
 )
 
 type tag struct {
-	fieldName   string
+	fieldName   string // the other side field name
 	noOverwrite bool
 	omitMissing bool
+	skip        bool // tag value "-", the field is excluded
+	required    bool
+	hasDefault  bool
+	defValue    string   // literal of `default=` option, parsed into the destination type
+	transforms  []string // names of `transform=` option
 }
 
-func parseTag(structField reflect.StructField, tagName tagKind) *tag {
+// parseTag returns nil if the field is not tagged. Options not set in the tag are taken from defaults.
+func parseTag(structField reflect.StructField, tagName tagKind, defaults tag) *tag {
 	v, ok := structField.Tag.Lookup(string(tagName))
 	if !ok {
 		return nil
 	}
 
+	if v == "-" {
+		return &tag{skip: true}
+	}
+
 	tg := &tag{}
+	*tg = defaults
 
 	vparts := strings.Split(v, ",")
 	if len(vparts) != 0 {
@@ -243,6 +219,27 @@ func parseTag(structField reflect.StructField, tagName tagKind) *tag {
 			tg.omitMissing = true
 			continue
 		}
+		if vpart == "overwrite" {
+			tg.noOverwrite = false
+			continue
+		}
+		if vpart == "mustexist" {
+			tg.omitMissing = false
+			continue
+		}
+		if vpart == "required" {
+			tg.required = true
+			continue
+		}
+		if strings.HasPrefix(vpart, "transform=") {
+			tg.transforms = strings.Split(strings.TrimPrefix(vpart, "transform="), "|")
+			continue
+		}
+		if strings.HasPrefix(vpart, "default=") {
+			tg.hasDefault = true
+			tg.defValue = strings.TrimPrefix(vpart, "default=")
+			continue
+		}
 	}
 
 	// no renaming, use field name
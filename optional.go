@@ -0,0 +1,181 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Optional values are types with a value and presence flag (like sql.NullString or Option[T]
+// types of third-party libraries). They are not copied recursively as structs, the missing
+// value behaves like nil and the present value is unwrapped when copied to a field of the
+// inner type (and wrapped when copied to an optional field).
+//
+// Besides database/sql Null types, any type implementing Optional (source side)
+// or OptionalSetter (destination side) is an optional value.
+
+// Optional is implemented by optional types used as a source.
+// UseValue returns the value and if it is present (a missing value behaves like nil).
+type Optional interface {
+	UseValue() (v any, present bool)
+}
+
+// OptionalSetter is implemented (usually with pointer receiver) by optional types
+// used as a destination. UseSetValue stores the present value v.
+// Types implementing only OptionalSetter are considered missing when they are zero
+// (e.g. for `nooverwrite`).
+type OptionalSetter interface {
+	UseSetValue(v any) error
+}
+
+var (
+	optionalType       = reflect.TypeOf((*Optional)(nil)).Elem()
+	optionalSetterType = reflect.TypeOf((*OptionalSetter)(nil)).Elem()
+)
+
+// isSQLNull reports if t is one of database/sql Null types (sql.NullString, ..., sql.Null[T]).
+// All of them have the value as the first field and Valid as the second one.
+func isSQLNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t.PkgPath() == "database/sql" &&
+		strings.HasPrefix(t.Name(), "Null") &&
+		t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" &&
+		t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// isOptional reports if t (or type t references) is an optional type.
+func isOptional(t reflect.Type) bool {
+	_, t = derefType(t)
+	return isSQLNull(t) || implements(t, optionalType) || implements(t, optionalSetterType)
+}
+
+// implements reports if t or *t implements the interface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+// asInterface returns v (or reference to v) as the interface.
+func asInterface(v reflect.Value, iface reflect.Type) (any, bool) {
+	if v.Type().Implements(iface) {
+		return v.Interface(), true
+	}
+	if !reflect.PtrTo(v.Type()).Implements(iface) {
+		return nil, false
+	}
+	if !v.CanAddr() {
+		cv := reflect.New(v.Type()).Elem()
+		cv.Set(v)
+		v = cv
+	}
+	return v.Addr().Interface(), true
+}
+
+// optionalValue returns the inner value of optional v (not a reference).
+// ok is false when v does not provide its value (only OptionalSetter is implemented).
+func optionalValue(v reflect.Value) (inner reflect.Value, present, ok bool) {
+	if isSQLNull(v.Type()) {
+		return v.Field(0), v.Field(1).Bool(), true
+	}
+
+	o, ok := asInterface(v, optionalType)
+	if !ok {
+		return reflect.Value{}, !v.IsZero(), false
+	}
+
+	x, present := o.(Optional).UseValue()
+	if !present || x == nil {
+		return reflect.Value{}, false, true
+	}
+
+	// addressable copy, so it can be referenced by the destination
+	xv := reflect.ValueOf(x)
+	inner = reflect.New(xv.Type()).Elem()
+	inner.Set(xv)
+	return inner, true, true
+}
+
+// optionalInnerType returns the type of the inner value of optional type t (not a reference).
+// The type of Optional is known only when UseValue of its zero value returns a typed value.
+func optionalInnerType(t reflect.Type) (reflect.Type, bool) {
+	if isSQLNull(t) {
+		return t.Field(0).Type, true
+	}
+	o, ok := asInterface(reflect.New(t).Elem(), optionalType)
+	if !ok {
+		return nil, false
+	}
+	x, _ := o.(Optional).UseValue()
+	if x == nil {
+		return nil, false
+	}
+	return reflect.TypeOf(x), true
+}
+
+// optionalPresent reports if the optional value v (not a reference) has a value.
+func optionalPresent(v reflect.Value) bool {
+	_, present, _ := optionalValue(v)
+	return present
+}
+
+// unwrapOptional returns the inner value of optional v (or a reference to optional).
+// ok is false when v is not an optional providing its value, present is false when it has no value.
+func unwrapOptional(v reflect.Value) (inner reflect.Value, present, ok bool) {
+	if !isOptional(v.Type()) {
+		return reflect.Value{}, false, false
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false, true
+		}
+		v = v.Elem()
+	}
+
+	inner, present, ok = optionalValue(v)
+	if !present {
+		inner = reflect.Value{}
+	}
+	return inner, present, ok
+}
+
+// setOptional wraps non nil v into optional field fv (allocating it when fv is a nil reference).
+func setOptional(fv, v reflect.Value, conv *converters) error {
+	_, v = derefValue(v)
+
+	target := fv
+	if fv.Kind() == reflect.Ptr {
+		target = reflect.New(fv.Type().Elem()).Elem()
+		if !fv.IsNil() {
+			target.Set(fv.Elem())
+		}
+	}
+
+	if isSQLNull(target.Type()) {
+		innerT := target.Field(0).Type()
+		if v.Type() != innerT {
+			cv, ok, err := conv.convert(v, innerT)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("types not assignable. dest %s, src %s", target.Type(), v.Type())
+			}
+			v = cv
+		}
+
+		target.Field(0).Set(v)
+		target.Field(1).SetBool(true)
+	} else {
+		if !target.CanAddr() || !target.Addr().Type().Implements(optionalSetterType) {
+			return fmt.Errorf("optional type %s does not implement OptionalSetter", target.Type())
+		}
+		if err := target.Addr().Interface().(OptionalSetter).UseSetValue(v.Interface()); err != nil {
+			return fmt.Errorf("setting %s: %w", target.Type(), err)
+		}
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(target.Addr())
+	}
+	return nil
+}
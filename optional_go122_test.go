@@ -0,0 +1,41 @@
+//go:build go1.22
+
+package use
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLNullGeneric(t *testing.T) {
+	type T struct {
+		F1 int            `usefrom:""`
+		F2 sql.Null[int]  `usefrom:""`
+		F3 *sql.Null[int] `usefrom:""`
+		F4 sql.Null[int]  `usefrom:""`
+	}
+
+	type TInput struct {
+		F1 sql.Null[int]
+		F2 *int
+		F3 int
+		F4 sql.Null[int]
+	}
+
+	obj := T{F1: 1, F4: sql.Null[int]{V: 4, Valid: true}}
+	_, err := From(&obj, &TInput{
+		F1: sql.Null[int]{V: 10, Valid: true},
+		F2: asRef(20),
+		F3: 30,
+		F4: sql.Null[int]{V: 40}, // invalid, not used
+	})
+	require.NoError(t, err)
+	require.Equal(t, T{
+		F1: 10,
+		F2: sql.Null[int]{V: 20, Valid: true},
+		F3: &sql.Null[int]{V: 30, Valid: true},
+		F4: sql.Null[int]{V: 4, Valid: true},
+	}, obj)
+}
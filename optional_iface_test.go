@@ -0,0 +1,151 @@
+package use
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testOption is like Option[T] of some third-party library.
+type testOption[T any] struct {
+	value T
+	ok    bool
+}
+
+func some[T any](v T) testOption[T] {
+	return testOption[T]{value: v, ok: true}
+}
+
+func (o testOption[T]) UseValue() (any, bool) {
+	return o.value, o.ok
+}
+
+func (o *testOption[T]) UseSetValue(v any) error {
+	tv, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", v)
+	}
+	o.value, o.ok = tv, true
+	return nil
+}
+
+// testMaybe is like another Option library, with pointer to value.
+type testMaybe[T any] struct {
+	Value *T
+}
+
+func (m *testMaybe[T]) UseValue() (any, bool) {
+	if m.Value == nil {
+		return nil, false
+	}
+	return *m.Value, true
+}
+
+func (m *testMaybe[T]) UseSetValue(v any) error {
+	tv, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", v)
+	}
+	m.Value = &tv
+	return nil
+}
+
+func TestOptionalSource(t *testing.T) {
+	type Nested struct {
+		Na int `usefrom:""`
+	}
+
+	type T struct {
+		F1 string  `usefrom:""`
+		F2 *int    `usefrom:""`
+		F3 string  `usefrom:""`
+		F4 *string `usefrom:",nooverwrite"`
+		F5 Nested  `usefrom:""` // optional value of struct is not copied recursively
+	}
+
+	type TInput struct {
+		F1 testOption[string]
+		F2 testMaybe[int]
+		F3 *testOption[string]
+		F4 testOption[string]
+		F5 testOption[Nested]
+	}
+
+	obj := T{F1: "old f1", F3: "old f3", F4: asRef("old f4")}
+	setFields, err := From(&obj, &TInput{})
+	require.NoError(t, err)
+	require.Empty(t, setFields)
+	require.Equal(t, T{F1: "old f1", F3: "old f3", F4: asRef("old f4")}, obj)
+
+	setFields, err = From(&obj, &TInput{
+		F1: some("new f1"),
+		F2: testMaybe[int]{Value: asRef(42)},
+		F3: asRef(some("new f3")),
+		F4: some("new f4"),
+		F5: some(Nested{Na: 5}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, T{F1: "new f1", F2: asRef(42), F3: "new f3", F4: asRef("old f4"), F5: Nested{Na: 5}}, obj)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F1", "F2", "F3", "F5"}, setFields)
+}
+
+func TestOptionalDestination(t *testing.T) {
+	type T struct {
+		F1 testOption[string]  `usefrom:""`
+		F2 testMaybe[int]      `usefrom:""`
+		F3 *testOption[string] `usefrom:""`
+		F4 testOption[string]  `usefrom:",nooverwrite"`
+		F5 testOption[string]  `usefrom:",nooverwrite"`
+		F6 testMaybe[int]      `usefrom:""` // from other optional type
+		F7 testOption[string]  `usefrom:""` // the same type
+	}
+
+	type TInput struct {
+		F1 *string
+		F2 int
+		F3 string
+		F4 string
+		F5 string
+		F6 testOption[int]
+		F7 testOption[string]
+	}
+
+	obj := T{F4: some("old f4"), F7: some("old f7")}
+	setFields, err := From(&obj, &TInput{
+		F1: asRef("new f1"),
+		F2: 2,
+		F3: "new f3",
+		F4: "new f4",
+		F5: "new f5",
+		F6: some(6),
+		F7: testOption[string]{}, // missing, not copied
+	})
+	require.NoError(t, err)
+	require.Equal(t, T{
+		F1: some("new f1"),
+		F2: testMaybe[int]{Value: asRef(2)},
+		F3: asRef(some("new f3")),
+		F4: some("old f4"),
+		F5: some("new f5"),
+		F6: testMaybe[int]{Value: asRef(6)},
+		F7: some("old f7"),
+	}, obj)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F1", "F2", "F3", "F5", "F6"}, setFields)
+
+	t.Run("setter error", func(t *testing.T) {
+		type TWrong struct {
+			F1 int
+		}
+		type TDest struct {
+			F1 testOption[string] `usefrom:""`
+		}
+		_, err := From(&TDest{}, &TWrong{F1: 1})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package use
+
+import (
+	"database/sql"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLNullSource(t *testing.T) {
+	type T struct {
+		F1 string     `usefrom:""`
+		F2 *int64     `usefrom:""`
+		F3 time.Time  `usefrom:""`
+		F4 *string    `usefrom:""`
+		F5 string     `usefrom:",nooverwrite"`
+		F6 *time.Time `usefrom:""`
+	}
+
+	type TInput struct {
+		F1 sql.NullString
+		F2 sql.NullInt64
+		F3 sql.NullTime
+		F4 *sql.NullString
+		F5 sql.NullString
+		F6 sql.NullTime
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	obj := T{F1: "old f1", F2: asRef(int64(1)), F5: "old f5"}
+
+	// invalid values behave like nil
+	setFields, err := From(&obj, &TInput{F4: &sql.NullString{}})
+	require.NoError(t, err)
+	require.Empty(t, setFields)
+	require.Equal(t, T{F1: "old f1", F2: asRef(int64(1)), F5: "old f5"}, obj)
+
+	setFields, err = From(&obj, &TInput{
+		F1: sql.NullString{String: "new f1", Valid: true},
+		F2: sql.NullInt64{Int64: 42, Valid: true},
+		F3: sql.NullTime{Time: now, Valid: true},
+		F4: &sql.NullString{String: "new f4", Valid: true},
+		F5: sql.NullString{String: "new f5", Valid: true},
+		F6: sql.NullTime{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, T{F1: "new f1", F2: asRef(int64(42)), F3: now, F4: asRef("new f4"), F5: "old f5", F6: &now}, obj)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F1", "F2", "F3", "F4", "F6"}, setFields)
+}
+
+func TestSQLNullDestination(t *testing.T) {
+	type T struct {
+		F1 sql.NullString  `usefrom:""`
+		F2 sql.NullInt64   `usefrom:",nooverwrite"`
+		F3 sql.NullInt64   `usefrom:",nooverwrite"`
+		F4 *sql.NullTime   `usefrom:""`
+		F5 sql.NullString  `usefrom:""`
+		F6 sql.NullFloat64 `usefrom:""`
+		F7 sql.NullBool    `usefrom:""`
+	}
+
+	type TInput struct {
+		F1 *string
+		F2 int64
+		F3 *int64
+		F4 time.Time
+		F5 *string
+		F6 sql.NullFloat64
+		F7 bool
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	obj := T{
+		F2: sql.NullInt64{Int64: 1, Valid: true}, // not overwritten, valid
+		F3: sql.NullInt64{Int64: 2},              // overwritten, invalid is nil
+		F5: sql.NullString{String: "old f5", Valid: true},
+		F6: sql.NullFloat64{Float64: 1.5, Valid: true},
+	}
+
+	setFields, err := From(&obj, &TInput{
+		F1: asRef("new f1"),
+		F2: 10,
+		F3: asRef(int64(20)),
+		F4: now,
+		F5: nil,
+		F6: sql.NullFloat64{Float64: 2.5, Valid: false},
+		F7: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, T{
+		F1: sql.NullString{String: "new f1", Valid: true},
+		F2: sql.NullInt64{Int64: 1, Valid: true},
+		F3: sql.NullInt64{Int64: 20, Valid: true},
+		F4: &sql.NullTime{Time: now, Valid: true},
+		F5: sql.NullString{String: "old f5", Valid: true},
+		F6: sql.NullFloat64{Float64: 1.5, Valid: true},
+		F7: sql.NullBool{Bool: true, Valid: true},
+	}, obj)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"F1", "F3", "F4", "F7"}, setFields)
+
+	t.Run("wrong inner type", func(t *testing.T) {
+		type TWrong struct {
+			F1 int `usefrom:""`
+		}
+		_, err := From(&T{}, &TWrong{F1: 1})
+		require.Error(t, err)
+	})
+}
+
+func TestSQLNullNotNested(t *testing.T) {
+	require.False(t, containsStructOrPtrToStruct(reflect.TypeOf(sql.NullString{})))
+	require.False(t, containsStructOrPtrToStruct(reflect.TypeOf(&sql.NullTime{})))
+	require.True(t, isNil(reflect.ValueOf(sql.NullString{})))
+	require.True(t, isNil(reflect.ValueOf(&sql.NullString{})))
+	require.False(t, isNil(reflect.ValueOf(&sql.NullString{Valid: true})))
+}
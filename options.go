@@ -0,0 +1,26 @@
+package use
+
+// Option modifies the behaviour of a single copy call.
+type Option func(*options)
+
+type options struct {
+	naming             naming
+	reverseNoOverwrite bool
+	undo               *Undo
+	mask               *mask
+	maxDepth           int
+	preserveShared     bool
+	tracer             Tracer
+	onlyChanged        bool
+	workers            int
+	envLookup          func(string) (string, bool)
+	defaults           *[]string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
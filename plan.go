@@ -0,0 +1,290 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Plan lists the field mappings applied between a destination and a source struct type.
+// Nested struct fields are listed together with their own fields, using dotted paths
+// in the same format as the returned setFields (e.g. "Address.City").
+type Plan struct {
+	Fields []PlanField
+}
+
+// PlanField is a single field mapping of a Plan.
+type PlanField struct {
+	Dest string // path of the destination field
+	Src  string // path of the source field (empty when only the default value is used)
+	Auto bool   // matched by name (Auto), not defined by a tag
+}
+
+type mode int
+
+const (
+	fromMode mode = iota
+	inMode
+	autoMode
+	reverseMode
+	jsonMode // source is a JSON object, see DecodeJSON
+	formMode // source is url.Values, see FromForm
+	envMode  // source is the environment, see FromEnv
+	flagMode // source are the set flags, see FromFlags
+)
+
+// rule is a compiled copy rule for one destination field.
+type rule struct {
+	destName string
+	srcName  string
+	nested   bool
+	auto     bool
+	missing  bool // the other side field does not exist (omitmissing), only traced
+	tag      *tag
+}
+
+// plan is a compiled list of rules for a pair of (dereferenced) struct types.
+type plan struct {
+	rules []*rule
+}
+
+type planKey struct {
+	mode mode
+	dest reflect.Type
+	src  reflect.Type
+	opts string // options the plan depends on
+}
+
+type planEntry struct {
+	p   *plan
+	err error
+}
+
+// plan returns the compiled plan from the cache, the tags are parsed only once per type pair.
+func (m *Mapper) plan(md mode, destT, srcT reflect.Type, o *options) (*plan, error) {
+	key := planKey{mode: md, dest: destT, src: srcT}
+	switch md {
+	case autoMode:
+		key.opts = o.naming.key()
+	case reverseMode:
+		key.opts = fmt.Sprint(o.reverseNoOverwrite)
+	}
+
+	if e, ok := m.plans.Load(key); ok {
+		return e.(*planEntry).p, e.(*planEntry).err
+	}
+
+	p, err := m.compilePlan(md, destT, srcT, o)
+	e, _ := m.plans.LoadOrStore(key, &planEntry{p: p, err: err})
+	return e.(*planEntry).p, e.(*planEntry).err
+}
+
+func (m *Mapper) compilePlan(md mode, destT, srcT reflect.Type, o *options) (*plan, error) {
+	switch md {
+	case fromMode:
+		return m.compileFrom(destT, srcT)
+	case inMode:
+		return m.compileIn(destT, srcT)
+	case autoMode:
+		return m.compileAuto(destT, srcT, o.naming)
+	case jsonMode:
+		return m.compileKeys(destT, isJSONNested)
+	case formMode, envMode, flagMode:
+		return m.compileKeys(destT, isKeyNested)
+	default:
+		return m.compileReverse(destT, srcT, o.reverseNoOverwrite)
+	}
+}
+
+// compileFrom builds the rules from `usefrom` tags on the destination struct.
+func (m *Mapper) compileFrom(destT, srcT reflect.Type) (*plan, error) {
+	p := &plan{}
+	for i := 0; i < destT.NumField(); i++ {
+		sf := destT.Field(i)
+		tg := parseTag(sf, m.fromTag, m.defaults)
+		if tg == nil || tg.skip {
+			continue
+		}
+
+		r, err := m.fromRule(sf, srcT, tg)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			p.rules = append(p.rules, r)
+		}
+	}
+
+	return p, nil
+}
+
+// fromRule returns the rule marked missing when the source field may be missing and it is.
+func (m *Mapper) fromRule(sf reflect.StructField, srcT reflect.Type, tg *tag) (*rule, error) {
+	if !sf.IsExported() {
+		return nil, fmt.Errorf("field %q is not settable", sf.Name)
+	}
+
+	if err := m.checkTag(tg, sf); err != nil {
+		return nil, err
+	}
+
+	ssf, ok := srcT.FieldByName(tg.fieldName)
+	if !ok || !ssf.IsExported() {
+		// the default is used instead of missing source field, required field is reported by checkRequired
+		if tg.hasDefault || tg.required {
+			return &rule{destName: sf.Name, tag: tg}, nil
+		}
+		// missing sub struct in source is not an error
+		if tg.omitMissing || containsStructOrPtrToStruct(sf.Type) {
+			return &rule{destName: sf.Name, tag: tg, missing: true}, nil
+		}
+		return nil, fmt.Errorf("invalid value of source field %q", tg.fieldName)
+	}
+
+	return &rule{
+		destName: sf.Name,
+		srcName:  tg.fieldName,
+		nested:   m.isNested(sf.Type, ssf.Type),
+		tag:      tg,
+	}, nil
+}
+
+// checkTag verifies the tag options depending on the destination field type.
+func (m *Mapper) checkTag(tg *tag, destField reflect.StructField) error {
+	if err := checkDefault(tg, destField); err != nil {
+		return err
+	}
+	return m.checkTransforms(tg, destField)
+}
+
+// compileIn builds the rules from `usein` tags on the source struct.
+func (m *Mapper) compileIn(destT, srcT reflect.Type) (*plan, error) {
+	p := &plan{}
+	for i := 0; i < srcT.NumField(); i++ {
+		sf := srcT.Field(i)
+		tg := parseTag(sf, m.inTag, m.defaults)
+		if tg == nil || tg.skip {
+			continue
+		}
+
+		r, err := m.inRule(sf, destT, tg)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			p.rules = append(p.rules, r)
+		}
+	}
+
+	return p, nil
+}
+
+// inRule returns the rule marked missing when the destination field may be missing and it is.
+func (m *Mapper) inRule(sf reflect.StructField, destT reflect.Type, tg *tag) (*rule, error) {
+	if !sf.IsExported() {
+		return nil, fmt.Errorf("source field %q is not exported", sf.Name)
+	}
+
+	dsf, ok := destT.FieldByName(tg.fieldName)
+	if !ok {
+		if tg.omitMissing {
+			return &rule{destName: tg.fieldName, srcName: sf.Name, tag: tg, missing: true}, nil
+		}
+		return nil, fmt.Errorf("destination field %q does not exist", tg.fieldName)
+	}
+	if !dsf.IsExported() {
+		return nil, fmt.Errorf("field %q is not settable", tg.fieldName)
+	}
+	if err := m.checkTag(tg, dsf); err != nil {
+		return nil, err
+	}
+
+	return &rule{
+		destName: tg.fieldName,
+		srcName:  sf.Name,
+		nested:   m.isNested(dsf.Type, sf.Type),
+		tag:      tg,
+	}, nil
+}
+
+// explain flattens the plan (including nested plans) of given types into pl.
+func (m *Mapper) explain(pl *Plan, md mode, destT, srcT reflect.Type, o *options, destPath, srcPath string, seen map[[2]reflect.Type]bool) error {
+	pair := [2]reflect.Type{destT, srcT}
+	if seen[pair] {
+		return nil
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
+	p, err := m.plan(md, destT, srcT, o)
+	if err != nil {
+		return fmt.Errorf("%w (on path: %q)", err, destPath)
+	}
+
+	for _, r := range p.rules {
+		if r.missing {
+			continue
+		}
+		dp, sp := addToFields(destPath, r.destName), addToFields(srcPath, r.srcName)
+		if r.srcName == "" {
+			sp = "" // only the default value
+		}
+		pl.Fields = append(pl.Fields, PlanField{Dest: dp, Src: sp, Auto: r.auto})
+		if !r.nested {
+			continue
+		}
+
+		dsf, _ := destT.FieldByName(r.destName)
+		ssf, _ := srcT.FieldByName(r.srcName)
+		ndt, nst := baseType(dsf.Type), baseType(ssf.Type)
+		if nst.Kind() != reflect.Struct {
+			continue
+		}
+		if err := m.explain(pl, md, ndt, nst, o, dp, sp, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compileKeys builds the rules from `usefrom` tags for a source of keyed values (like JSON object),
+// the source names are the keys. isNested tells which fields are applied recursively.
+func (m *Mapper) compileKeys(destT reflect.Type, isNested func(reflect.Type) bool) (*plan, error) {
+	p := &plan{}
+	for i := 0; i < destT.NumField(); i++ {
+		sf := destT.Field(i)
+		tg := parseTag(sf, m.fromTag, m.defaults)
+		if tg == nil || tg.skip {
+			continue
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("field %q is not settable", sf.Name)
+		}
+		if err := m.checkTag(tg, sf); err != nil {
+			return nil, err
+		}
+
+		p.rules = append(p.rules, &rule{
+			destName: sf.Name,
+			srcName:  tg.fieldName,
+			nested:   isNested(sf.Type),
+			tag:      tg,
+		})
+	}
+	return p, nil
+}
+
+// keyRule returns the rule of the source key (see compileKeys), the same name has precedence.
+func (p *plan) keyRule(key string) *rule {
+	var found *rule
+	for _, fr := range p.rules {
+		if fr.srcName == key {
+			return fr
+		}
+		if found == nil && strings.EqualFold(fr.srcName, key) {
+			found = fr
+		}
+	}
+	return found
+}
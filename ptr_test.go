@@ -0,0 +1,102 @@
+package use
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiLevelPointers(t *testing.T) {
+	type Nested struct {
+		Name string `usefrom:"" usein:""`
+	}
+
+	type Dest struct {
+		F1 string   `usefrom:"" usein:""`
+		F2 *string  `usefrom:"" usein:""`
+		F3 **string `usefrom:"" usein:""`
+		F4 *string  `usefrom:"" usein:""`
+		F5 string   `usefrom:"" usein:""`
+		F6 **string `usefrom:"" usein:""`
+		N1 **Nested `usefrom:"" usein:""`
+		N2 *Nested  `usefrom:"" usein:""`
+		N3 **Nested `usefrom:"" usein:""`
+	}
+
+	type Src struct {
+		F1 **string `usein:""`
+		F2 **string `usein:""`
+		F3 string   `usein:""`
+		F4 **string `usein:""`
+		F5 **string `usein:""`
+		F6 **string `usein:""`
+		N1 *Nested  `usein:""`
+		N2 **Nested `usein:""`
+		N3 **Nested `usein:""`
+	}
+
+	null := func() **string { return new(*string) }
+	newObj := func() Dest {
+		return Dest{
+			F4: asRef("old f4"),
+			F5: "old f5",
+			F6: asRef(asRef("old f6")),
+			N3: asRef(&Nested{Name: "old n3"}),
+		}
+	}
+
+	src := Src{
+		F1: asRef(asRef("new f1")),
+		F2: asRef(asRef("new f2")),
+		F3: "new f3",
+		F4: null(), // present but null clears the destination
+		F5: null(),
+		F6: nil, // missing, not copied
+		N1: &Nested{Name: "new n1"},
+		N2: asRef(&Nested{Name: "new n2"}),
+		N3: new(*Nested),
+	}
+
+	expected := Dest{
+		F1: "new f1",
+		F2: asRef("new f2"),
+		F3: asRef(asRef("new f3")),
+		F4: nil,
+		F5: "",
+		F6: asRef(asRef("old f6")),
+		N1: asRef(&Nested{Name: "new n1"}),
+		N2: &Nested{Name: "new n2"},
+		N3: new(*Nested),
+	}
+	expectedSetFields := []string{"F1", "F2", "F3", "F4", "F5", "N1.Name", "N2.Name", "N3"}
+
+	for name, fn := range map[string]func(dest, src any, opts ...Option) ([]string, error){
+		"from": From,
+		"in":   In,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dest := newObj()
+			var undo Undo
+			setFields, err := fn(&dest, &src, WithUndo(&undo))
+			require.NoError(t, err)
+			require.Equal(t, expected, dest)
+			sort.Strings(setFields)
+			require.Equal(t, expectedSetFields, setFields)
+
+			undo()
+			require.Equal(t, newObj(), dest)
+		})
+	}
+
+	t.Run("null to null", func(t *testing.T) {
+		type D struct {
+			F **string `usefrom:""`
+		}
+		dest := D{F: asRef(asRef("old"))}
+		_, err := From(&dest, &D{F: null()})
+		require.NoError(t, err)
+		require.NotNil(t, dest.F)
+		require.Nil(t, *dest.F)
+	})
+}
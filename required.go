@@ -0,0 +1,87 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RequiredError is returned when the source does not provide fields tagged with `required`
+// option (the field is missing or nil). Nothing is copied in such case.
+type RequiredError struct {
+	Paths []string // paths of all missing fields, in the same format as setFields
+}
+
+func (e *RequiredError) Error() string {
+	return fmt.Sprintf("required fields are missing: %s", strings.Join(e.Paths, ", "))
+}
+
+// checkRequired verifies the source provides all required fields, including nested structs
+// present in the source. All missing fields are reported together.
+func (r *run) checkRequired(dest, src any) error {
+	destT, err := structType(dest)
+	if err != nil {
+		return nil // reported by copy
+	}
+	sv, ok := baseValue(reflect.ValueOf(src))
+	if !ok || sv.Kind() != reflect.Struct {
+		return nil // reported by copy
+	}
+
+	var missing []string
+	r.missingRequired(destT, sv, "", map[visitKey]bool{}, &missing)
+	if len(missing) > 0 {
+		return &RequiredError{Paths: missing}
+	}
+	return nil
+}
+
+// missingRequired appends to missing the paths of required fields not provided by src (struct value).
+func (r *run) missingRequired(destT reflect.Type, src reflect.Value, path string, active map[visitKey]bool, missing *[]string) {
+	if src.CanAddr() {
+		key := newVisitKey(src.Addr())
+		if active[key] {
+			return // reference cycle
+		}
+		active[key] = true
+		defer delete(active, key)
+	}
+
+	p, err := r.m.plan(r.mode, destT, src.Type(), r.opts)
+	if err != nil {
+		return // reported by copy
+	}
+
+	for _, fr := range p.rules {
+		fieldPath := addToFields(path, fr.destName)
+		if fr.missing || !r.opts.mask.enters(fieldPath) {
+			continue
+		}
+
+		var fv reflect.Value
+		if fr.srcName != "" {
+			fv = src.FieldByName(fr.srcName)
+		}
+		if isNil(fv) || isNullRef(fv) {
+			if fr.tag.required {
+				*missing = append(*missing, fieldPath)
+			}
+			continue
+		}
+		if !fr.nested {
+			continue
+		}
+
+		dsf, _ := destT.FieldByName(fr.destName)
+		if fv.Kind() != reflect.Array {
+			nv, _ := baseValue(fv)
+			r.missingRequired(baseType(dsf.Type), nv, fieldPath, active, missing)
+			continue
+		}
+		for i := 0; i < fv.Len(); i++ {
+			if nv, ok := baseValue(fv.Index(i)); ok {
+				r.missingRequired(baseType(dsf.Type.Elem()), nv, fmt.Sprintf("%s[%d]", fieldPath, i), active, missing)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package use
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequired(t *testing.T) {
+	type Address struct {
+		City   string  `usefrom:"" usein:""`
+		Street *string `usefrom:",required" usein:",required"`
+	}
+
+	type Entity struct {
+		ID      *int       `usefrom:",required"`
+		Name    string     `usefrom:",required"`
+		Email   *string    `usefrom:""`
+		Code    string     `usefrom:",required"`
+		Address *Address   `usefrom:""`
+		Offices [2]Address `usefrom:""`
+	}
+
+	type Input struct {
+		ID      *int
+		Name    string
+		Email   *string
+		Address *Address
+		Offices [2]Address
+		// Code is missing
+	}
+
+	t.Run("all reported", func(t *testing.T) {
+		dest := Entity{}
+		_, err := From(&dest, &Input{
+			Name:    "name",
+			Address: &Address{City: "city"},
+			Offices: [2]Address{{Street: asRef("street")}},
+		})
+
+		var rerr *RequiredError
+		require.True(t, errors.As(err, &rerr))
+		require.Equal(t, []string{"ID", "Code", "Address.Street", "Offices[1].Street"}, rerr.Paths)
+		require.Equal(t, Entity{}, dest, "nothing is copied")
+	})
+
+	t.Run("nil nested struct", func(t *testing.T) {
+		type Dest struct {
+			Name    string   `usefrom:""`
+			Address *Address `usefrom:""`
+		}
+		dest := Dest{}
+		_, err := From(&dest, &Input{Name: "name"})
+		require.NoError(t, err)
+		require.Equal(t, Dest{Name: "name"}, dest)
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		dest := Entity{}
+		_, err := From(&dest, &Input{Email: asRef("email")}, Mask("Email"))
+		require.NoError(t, err)
+		require.Equal(t, asRef("email"), dest.Email)
+	})
+
+	t.Run("in", func(t *testing.T) {
+		type Patch struct {
+			Street *string `usein:",required"`
+		}
+		_, err := In(&Address{}, &Patch{})
+		var rerr *RequiredError
+		require.True(t, errors.As(err, &rerr))
+		require.Equal(t, []string{"Street"}, rerr.Paths)
+
+		_, err = In(&Address{}, &Patch{Street: asRef("street")})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package use
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Reverse copies values from src to dest using the `usefrom` tags on the source struct
+// backwards. It is the opposite direction of From: when dest is filled from src by
+// From(&entity, &input), Reverse(&response, &entity) fills the response from the entity
+// without the need of a second set of tags.
+//
+// Renames and nested structs are kept, `omitmissing` tolerates missing destination fields.
+// `nooverwrite` is ignored, unless ReverseNoOverwrite option is used.
+// It is an error when more source fields are mapped from the same field,
+// because the reverse mapping is ambiguous.
+//
+// Example:
+//
+//	type Entity struct {
+//		Name  string `usefrom:"FullName"`
+//		Email string `usefrom:""`
+//	}
+//
+//	type Response struct {
+//		FullName string
+//		Email    *string
+//	}
+//
+//	setFields, err := Reverse(&response, &entity)
+//	// response.FullName == entity.Name, *response.Email == entity.Email
+func Reverse(dest, src any, opts ...Option) (setFields []string, err error) {
+	return defaultMapper.Reverse(dest, src, opts...)
+}
+
+// ReverseNoOverwrite makes Reverse respect the `nooverwrite` tag option.
+func ReverseNoOverwrite() Option {
+	return func(o *options) {
+		o.reverseNoOverwrite = true
+	}
+}
+
+// compileReverse builds the rules from `usefrom` tags on the source struct.
+func (m *Mapper) compileReverse(destT, srcT reflect.Type, keepNoOverwrite bool) (*plan, error) {
+	mappedFrom := map[string]string{} // destination field -> source field
+	p := &plan{}
+	for i := 0; i < srcT.NumField(); i++ {
+		sf := srcT.Field(i)
+		tg := parseTag(sf, m.fromTag, m.defaults)
+		if tg == nil || tg.skip {
+			continue
+		}
+
+		if prev, ok := mappedFrom[tg.fieldName]; ok {
+			return nil, fmt.Errorf("ambiguous reverse mapping: fields %q and %q are both from %q", prev, sf.Name, tg.fieldName)
+		}
+		mappedFrom[tg.fieldName] = sf.Name
+
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("source field %q is not exported", sf.Name)
+		}
+
+		dsf, ok := destT.FieldByName(tg.fieldName)
+		if !ok {
+			// missing sub struct in destination is not an error (as in From)
+			if tg.omitMissing || containsStructOrPtrToStruct(sf.Type) {
+				continue
+			}
+			return nil, fmt.Errorf("destination field %q does not exist", tg.fieldName)
+		}
+		if !dsf.IsExported() {
+			return nil, fmt.Errorf("field %q is not settable", tg.fieldName)
+		}
+
+		rtg := *tg
+		rtg.noOverwrite = tg.noOverwrite && keepNoOverwrite
+		rtg.hasDefault = false // defaults, requirements and transforms are for the forward direction
+		rtg.required = false
+		rtg.transforms = nil
+
+		p.rules = append(p.rules, &rule{
+			destName: tg.fieldName,
+			srcName:  sf.Name,
+			nested:   m.isNested(dsf.Type, sf.Type),
+			tag:      &rtg,
+		})
+	}
+
+	return p, nil
+}
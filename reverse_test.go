@@ -0,0 +1,80 @@
+package use
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverse(t *testing.T) {
+	type Address struct {
+		City string `usefrom:"Town"`
+	}
+
+	type AddressDTO struct {
+		Town *string
+	}
+
+	type Entity struct {
+		Name    string   `usefrom:"FullName"`
+		Email   *string  `usefrom:",nooverwrite"`
+		Age     int      `usefrom:",omitmissing"`
+		Address *Address `usefrom:"Addr"`
+		Secret  string
+	}
+
+	type DTO struct {
+		FullName *string
+		Email    *string
+		Addr     *AddressDTO
+	}
+
+	entity := Entity{}
+	_, err := From(&entity, &DTO{FullName: asRef("John"), Email: asRef("john@example.com"), Addr: &AddressDTO{Town: asRef("Prague")}})
+	require.NoError(t, err)
+
+	entity.Secret = "secret"
+	resp := DTO{Email: asRef("old@example.com")}
+	setFields, err := Reverse(&resp, &entity)
+	require.NoError(t, err)
+	require.Equal(t, DTO{FullName: asRef("John"), Email: asRef("john@example.com"), Addr: &AddressDTO{Town: asRef("Prague")}}, resp)
+
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Addr.Town", "Email", "FullName"}, setFields)
+
+	t.Run("nooverwrite", func(t *testing.T) {
+		resp := DTO{Email: asRef("old@example.com")}
+		_, err := Reverse(&resp, &entity, ReverseNoOverwrite())
+		require.NoError(t, err)
+		require.Equal(t, "old@example.com", *resp.Email)
+		require.Equal(t, "John", *resp.FullName)
+	})
+}
+
+func TestReverseErrors(t *testing.T) {
+	t.Run("ambiguous", func(t *testing.T) {
+		type Entity struct {
+			F1a string `usefrom:"InpF1"`
+			F1b string `usefrom:"InpF1"`
+		}
+		type DTO struct {
+			InpF1 string
+		}
+
+		_, err := Reverse(&DTO{}, &Entity{})
+		require.Error(t, err)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		type Entity struct {
+			F1 string `usefrom:"InpF1"`
+		}
+		type DTO struct {
+			F1 string
+		}
+
+		_, err := Reverse(&DTO{}, &Entity{})
+		require.Error(t, err)
+	})
+}
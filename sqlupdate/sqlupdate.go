@@ -0,0 +1,187 @@
+// Package sqlupdate builds the SET part of SQL UPDATE statement from the fields
+// changed by use.From (or other functions of package use returning setFields).
+//
+// The struct paths are mapped to column names with the `db` tag (fields without
+// the tag use snake_case of the field name). Nested structs are flattened,
+// their columns are prefixed with the column name of the parent field:
+//
+//	type Address struct {
+//		City string `db:"city"`
+//	}
+//
+//	type User struct {
+//		Name    string   `db:"name"`
+//		Address *Address `db:"addr"`
+//	}
+//
+//	setFields, _ := use.From(&user, &input) // ["Name", "Address.City"]
+//	set, args, err := sqlupdate.Set(&user, setFields, sqlupdate.Postgres)
+//	// set:  "SET name = $1, addr_city = $2"
+//	// args: []any{"John", "Prague"}
+//	query := "UPDATE users " + set + fmt.Sprintf(" WHERE id = $%d", len(args)+1)
+//
+// Array elements (paths like "Addrs[0].City") have no columns, such changes are
+// rejected with ErrArrayElement.
+package sqlupdate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Dialect defines the placeholders of the statement.
+type Dialect int
+
+const (
+	Postgres Dialect = iota // $1, $2, ...
+	MySQL                   // ?, ?, ...
+	SQLite                  // ?, ?, ...
+)
+
+// ErrNoChanges is returned when there is nothing to update.
+var ErrNoChanges = errors.New("no changes")
+
+// ErrArrayElement is returned for a change of an array element (like "Addrs[0].City"),
+// the elements are not mapped to columns.
+var ErrArrayElement = errors.New("array elements are not columns")
+
+// Builder builds SET fragments. The zero value uses Postgres placeholders,
+// `db` tag and "_" as the separator of nested columns.
+type Builder struct {
+	Dialect   Dialect
+	Tag       string // tag with column name, default "db"
+	Separator string // separator of nested struct columns, default "_"
+}
+
+// Set returns the SET fragment and its args for changed fields of dest (reference to struct)
+// with the placeholders of the dialect. See Builder.Set.
+func Set(dest any, changes []string, d Dialect) (string, []any, error) {
+	return Builder{Dialect: d}.Set(dest, changes)
+}
+
+// Set returns "SET col1 = $1, col2 = $2" fragment and the current values of changed fields
+// of dest (reference to struct) as args. Changes are paths in the format of setFields
+// ("Name", "Address.City"), duplicates are ignored. Nil pointers are passed as nil args
+// (NULL), other pointers are dereferenced. Changes of array elements return ErrArrayElement.
+func (b Builder) Set(dest any, changes []string) (string, []any, error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return "", nil, errors.New("dest must be a non nil reference to struct")
+	}
+
+	var sb strings.Builder
+	var args []any
+	seen := map[string]bool{}
+	for _, path := range changes {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		column, fv, err := b.column(v.Elem(), path)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(args) == 0 {
+			sb.WriteString("SET ")
+		} else {
+			sb.WriteString(", ")
+		}
+		args = append(args, arg(fv))
+		sb.WriteString(column)
+		sb.WriteString(" = ")
+		sb.WriteString(b.placeholder(len(args)))
+	}
+
+	if len(args) == 0 {
+		return "", nil, ErrNoChanges
+	}
+
+	return sb.String(), args, nil
+}
+
+// column resolves the path to the column name and the field value.
+func (b Builder) column(v reflect.Value, path string) (string, reflect.Value, error) {
+	tagName, sep := b.Tag, b.Separator
+	if tagName == "" {
+		tagName = "db"
+	}
+	if sep == "" {
+		sep = "_"
+	}
+
+	var columns []string
+	for _, name := range strings.Split(path, ".") {
+		if strings.Contains(name, "[") {
+			return "", reflect.Value{}, fmt.Errorf("path %q: %w", path, ErrArrayElement)
+		}
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return "", reflect.Value{}, fmt.Errorf("path %q: nil struct on the path", path)
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return "", reflect.Value{}, fmt.Errorf("path %q: %s is not a struct", path, v.Type())
+		}
+
+		sf, ok := v.Type().FieldByName(name)
+		if !ok || !sf.IsExported() {
+			return "", reflect.Value{}, fmt.Errorf("path %q: field %q does not exist", path, name)
+		}
+
+		column, ok := sf.Tag.Lookup(tagName)
+		column, _, _ = strings.Cut(column, ",")
+		switch {
+		case column == "-":
+			return "", reflect.Value{}, fmt.Errorf("path %q: field %q is not a column", path, name)
+		case !ok || column == "":
+			column = toSnakeCase(name)
+		}
+
+		columns = append(columns, column)
+		v = v.FieldByIndex(sf.Index)
+	}
+
+	return strings.Join(columns, sep), v, nil
+}
+
+func (b Builder) placeholder(n int) string {
+	if b.Dialect == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// arg dereferences pointers, nil pointer is nil (NULL).
+func arg(v reflect.Value) any {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// toSnakeCase converts CamelCase name to snake_case (UserID -> user_id).
+func toSnakeCase(name string) string {
+	rs := []rune(name)
+	var sb strings.Builder
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
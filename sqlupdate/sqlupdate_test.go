@@ -0,0 +1,117 @@
+package sqlupdate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dacz/use"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	City   string  `db:"city" usefrom:""`
+	Street *string `db:"street" usefrom:""`
+}
+
+type User struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name" usefrom:""`
+	Email     *string   `db:"email,omitempty" usefrom:""`
+	LastLogin time.Time `usefrom:",omitmissing"`
+	Address   *Address  `db:"addr" usefrom:""`
+	Secret    string    `db:"-" usefrom:",omitmissing"`
+}
+
+type Input struct {
+	Name    *string
+	Email   *string
+	Address *Address
+}
+
+func TestSetFromChanges(t *testing.T) {
+	user := User{ID: 1, Name: "John", Email: asRef("john@example.com")}
+	setFields, err := use.From(&user, &Input{Name: asRef("Jane"), Address: &Address{City: "Prague"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Name", "Address.City"}, setFields)
+
+	set, args, err := Set(&user, setFields, Postgres)
+	require.NoError(t, err)
+	require.Equal(t, "SET name = $1, addr_city = $2", set)
+	require.Equal(t, []any{"Jane", "Prague"}, args)
+}
+
+func TestSetDialects(t *testing.T) {
+	user := User{Name: "Jane", Email: nil, LastLogin: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Address: &Address{Street: asRef("Main")}}
+	changes := []string{"Name", "Email", "LastLogin", "Address.Street", "Name"}
+	expectedArgs := []any{"Jane", nil, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "Main"}
+
+	for d, expected := range map[Dialect]string{
+		Postgres: "SET name = $1, email = $2, last_login = $3, addr_street = $4",
+		MySQL:    "SET name = ?, email = ?, last_login = ?, addr_street = ?",
+		SQLite:   "SET name = ?, email = ?, last_login = ?, addr_street = ?",
+	} {
+		set, args, err := Set(&user, changes, d)
+		require.NoError(t, err)
+		require.Equal(t, expected, set)
+		require.Equal(t, expectedArgs, args)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	type Nested struct {
+		Value int `col:"value"`
+	}
+	type T struct {
+		Name   string  `col:"full_name"`
+		Nested *Nested `col:"nested"`
+	}
+
+	b := Builder{Dialect: MySQL, Tag: "col", Separator: "__"}
+	set, args, err := b.Set(&T{Name: "n", Nested: &Nested{Value: 42}}, []string{"Name", "Nested.Value"})
+	require.NoError(t, err)
+	require.Equal(t, "SET full_name = ?, nested__value = ?", set)
+	require.Equal(t, []any{"n", 42}, args)
+}
+
+func TestSetErrors(t *testing.T) {
+	user := User{}
+
+	_, _, err := Set(&user, nil, Postgres)
+	require.ErrorIs(t, err, ErrNoChanges)
+
+	_, _, err = Set(&user, []string{"Secret"}, Postgres)
+	require.Error(t, err)
+
+	_, _, err = Set(&user, []string{"Unknown"}, Postgres)
+	require.Error(t, err)
+
+	_, _, err = Set(&user, []string{"Address.City"}, Postgres) // nil Address
+	require.Error(t, err)
+
+	_, _, err = Set(user, []string{"Name"}, Postgres)
+	require.Error(t, err)
+}
+
+func TestSetArrayElements(t *testing.T) {
+	type Person struct {
+		Name  string     `db:"name" usefrom:""`
+		Addrs [2]Address `db:"addrs" usefrom:""`
+	}
+	type PersonInput struct {
+		Name  *string
+		Addrs [2]Address
+	}
+
+	person := Person{}
+	setFields, err := use.From(&person, &PersonInput{Name: asRef("John"), Addrs: [2]Address{{City: "Prague"}}})
+	require.NoError(t, err)
+	require.Contains(t, setFields, "Addrs[0].City")
+
+	_, _, err = Set(&person, setFields, Postgres)
+	require.ErrorIs(t, err, ErrArrayElement)
+	require.ErrorContains(t, err, `path "Addrs[0].City"`)
+}
+
+func asRef[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,46 @@
+package use
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// textConvertible reports if the string can be parsed into destT (encoding.TextUnmarshaler)
+// or srcT formatted into string destT (encoding.TextMarshaler).
+func textConvertible(srcT, destT reflect.Type) bool {
+	if srcT.Kind() == reflect.String && implements(destT, textUnmarshalerType) {
+		return true
+	}
+	return destT.Kind() == reflect.String && implements(srcT, textMarshalerType)
+}
+
+// convertText converts the (dereferenced) string sv to dt with UnmarshalText
+// or sv to string dt with MarshalText. ok is false when neither applies.
+func convertText(sv reflect.Value, dt reflect.Type) (cv reflect.Value, ok bool, err error) {
+	if !textConvertible(sv.Type(), dt) {
+		return reflect.Value{}, false, nil
+	}
+
+	cv = reflect.New(dt).Elem()
+	if sv.Kind() == reflect.String && implements(dt, textUnmarshalerType) {
+		u, _ := asInterface(cv, textUnmarshalerType)
+		if err := u.(encoding.TextUnmarshaler).UnmarshalText([]byte(sv.String())); err != nil {
+			return reflect.Value{}, true, fmt.Errorf("parsing %q as %s: %w", sv.String(), dt, err)
+		}
+		return cv, true, nil
+	}
+
+	m, _ := asInterface(sv, textMarshalerType)
+	b, err := m.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return reflect.Value{}, true, fmt.Errorf("formatting %s: %w", sv.Type(), err)
+	}
+	cv.SetString(string(b))
+	return cv, true, nil
+}
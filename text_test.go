@@ -0,0 +1,140 @@
+package use
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testLevel int
+
+const (
+	testLevelLow testLevel = iota + 1
+	testLevelHigh
+)
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case testLevelLow:
+		return []byte("low"), nil
+	case testLevelHigh:
+		return []byte("high"), nil
+	}
+	return nil, fmt.Errorf("invalid level %d", int(l))
+}
+
+func (l *testLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "low":
+		*l = testLevelLow
+	case "high":
+		*l = testLevelHigh
+	default:
+		return fmt.Errorf("unknown level %q", b)
+	}
+	return nil
+}
+
+func TestText(t *testing.T) {
+	type Entity struct {
+		IP      netip.Addr `usefrom:""`
+		At      time.Time  `usefrom:""`
+		Amount  *big.Int   `usefrom:""`
+		Level   testLevel  `usefrom:""`
+		Skipped *testLevel `usefrom:""`
+	}
+
+	type Form struct {
+		IP      string
+		At      *string
+		Amount  string
+		Level   string
+		Skipped *string
+	}
+
+	dest := Entity{}
+	setFields, err := From(&dest, &Form{
+		IP:     "10.0.0.1",
+		At:     asRef("2024-05-01T10:00:00Z"),
+		Amount: "123456789012345678901234567890",
+		Level:  "high",
+	})
+	require.NoError(t, err)
+	sort.Strings(setFields)
+	require.Equal(t, []string{"Amount", "At", "IP", "Level"}, setFields)
+
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.Equal(t, Entity{
+		IP:     netip.MustParseAddr("10.0.0.1"),
+		At:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Amount: amount,
+		Level:  testLevelHigh,
+	}, dest)
+
+	t.Run("reverse", func(t *testing.T) {
+		form := Form{}
+		_, err := Reverse(&form, &dest)
+		require.NoError(t, err)
+		require.Equal(t, Form{
+			IP:     "10.0.0.1",
+			At:     asRef("2024-05-01T10:00:00Z"),
+			Amount: "123456789012345678901234567890",
+			Level:  "high",
+		}, form)
+	})
+
+	t.Run("structs without exported fields", func(t *testing.T) {
+		type Row struct {
+			IP     netip.Addr
+			At     time.Time
+			Amount big.Int
+		}
+		type Record struct {
+			IP     netip.Addr `usefrom:"" usein:""`
+			At     time.Time  `usefrom:"" usein:""`
+			Amount big.Int    `usefrom:"" usein:""`
+		}
+
+		row := Row{IP: dest.IP, At: dest.At, Amount: *amount}
+		rec := Record{}
+		setFields, err := From(&rec, &row)
+		require.NoError(t, err)
+		require.Equal(t, []string{"IP", "At", "Amount"}, setFields)
+		require.Equal(t, Record(row), rec)
+
+		back := Row{}
+		setFields, err = In(&back, &rec)
+		require.NoError(t, err)
+		require.Equal(t, []string{"IP", "At", "Amount"}, setFields)
+		require.Equal(t, row, back)
+
+		back = Row{}
+		setFields, err = Reverse(&back, &rec)
+		require.NoError(t, err)
+		require.Equal(t, []string{"IP", "At", "Amount"}, setFields)
+		require.Equal(t, row, back)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		type Patch struct {
+			Level string `usein:""`
+		}
+		dest := Entity{}
+		_, err := In(&dest, &Patch{Level: "medium"})
+		require.ErrorContains(t, err, `"Level"`)
+		require.ErrorContains(t, err, `unknown level "medium"`)
+	})
+
+	t.Run("format error", func(t *testing.T) {
+		type Out struct {
+			Level string `usefrom:""`
+		}
+		_, err := From(&Out{}, &Entity{Level: 42})
+		require.ErrorContains(t, err, "invalid level 42")
+	})
+}
@@ -0,0 +1,56 @@
+package use
+
+// Reason tells why a destination field was (or was not) changed.
+type Reason string
+
+const (
+	ReasonSet         Reason = "set"         // set from the source
+	ReasonCleared     Reason = "cleared"     // cleared by "present but null" source (like **string)
+	ReasonDefault     Reason = "default"     // set to the `default=` value
+	ReasonNil         Reason = "source nil"  // not set, the source is nil
+	ReasonNoOverwrite Reason = "nooverwrite" // not set, the destination is not nil
+	ReasonUnchanged   Reason = "unchanged"   // not set, the value equals the current one (OnlyChanged)
+	ReasonMissing     Reason = "missing"     // not set, the other side field does not exist (omitmissing)
+	ReasonMasked      Reason = "masked"      // not set, excluded by Mask
+	ReasonNested      Reason = "nested"      // nested struct, its fields are evaluated
+	ReasonShared      Reason = "shared"      // references the already copied struct (PreserveShared)
+)
+
+// Event is the decision made for one evaluated destination field.
+type Event struct {
+	Path   string // path of the destination field in the same format as setFields
+	Reason Reason
+}
+
+// Tracer receives an event for every evaluated field of a copy call.
+type Tracer interface {
+	Trace(e Event)
+}
+
+// TracerFunc is a function implementing Tracer.
+type TracerFunc func(e Event)
+
+// Trace calls f(e).
+func (f TracerFunc) Trace(e Event) {
+	f(e)
+}
+
+// WithTracer sends the decisions about the fields to t, e.g. to find out why a field
+// was not updated. Without a tracer no events are created.
+//
+// Example:
+//
+//	_, err := use.From(&entity, &input, use.WithTracer(use.TracerFunc(func(e use.Event) {
+//		log.Printf("%s: %s", e.Path, e.Reason)
+//	})))
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.tracer = t
+	}
+}
+
+func (r *run) trace(path string, reason Reason) {
+	if r.opts.tracer != nil {
+		r.opts.tracer.Trace(Event{Path: path, Reason: reason})
+	}
+}
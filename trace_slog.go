@@ -0,0 +1,30 @@
+//go:build go1.21
+
+package use
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogTracer returns a Tracer logging every event to l at debug level
+// (message "use: field" with attributes "path" and "reason").
+//
+// Example:
+//
+//	_, err := use.From(&entity, &input, use.WithTracer(use.SlogTracer(logger)))
+func SlogTracer(l *slog.Logger) Tracer {
+	return slogTracer{l: l}
+}
+
+type slogTracer struct {
+	l *slog.Logger
+}
+
+func (t slogTracer) Trace(e Event) {
+	ctx := context.Background()
+	if !t.l.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	t.l.LogAttrs(ctx, slog.LevelDebug, "use: field", slog.String("path", e.Path), slog.String("reason", string(e.Reason)))
+}
@@ -0,0 +1,32 @@
+//go:build go1.21
+
+package use
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogTracer(t *testing.T) {
+	type T struct {
+		F1 *string `usefrom:""`
+		F2 *string `usefrom:""`
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := From(&T{}, &T{F1: asRef("f1")}, WithTracer(SlogTracer(logger)))
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `msg="use: field" path=F1 reason=set`)
+	require.Contains(t, buf.String(), `msg="use: field" path=F2 reason="source nil"`)
+
+	buf.Reset()
+	logger = slog.New(slog.NewTextHandler(&buf, nil)) // info level
+	_, err = From(&T{}, &T{F1: asRef("f1")}, WithTracer(SlogTracer(logger)))
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+}
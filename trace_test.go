@@ -0,0 +1,57 @@
+package use
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer(t *testing.T) {
+	type Address struct {
+		City string `usefrom:""`
+	}
+
+	type Dest struct {
+		Name    *string  `usefrom:""`
+		Email   *string  `usefrom:",nooverwrite"`
+		Phone   *string  `usefrom:""`
+		Note    string   `usefrom:",omitmissing"`
+		Status  string   `usefrom:",default=active"`
+		Secret  string   `usefrom:""`
+		Address *Address `usefrom:""`
+	}
+
+	type Src struct {
+		Name    *string
+		Email   *string
+		Phone   *string
+		Status  *string
+		Secret  string
+		Address *Address
+	}
+
+	var events []Event
+	tracer := TracerFunc(func(e Event) {
+		events = append(events, e)
+	})
+
+	dest := Dest{Email: asRef("old")}
+	_, err := From(&dest, &Src{
+		Name:    asRef("name"),
+		Email:   asRef("email"),
+		Secret:  "secret",
+		Address: &Address{City: "city"},
+	}, WithTracer(tracer), Mask("Name", "Email", "Phone", "Status", "Address"))
+	require.NoError(t, err)
+
+	require.Equal(t, []Event{
+		{Path: "Name", Reason: ReasonSet},
+		{Path: "Email", Reason: ReasonNoOverwrite},
+		{Path: "Phone", Reason: ReasonNil},
+		{Path: "Note", Reason: ReasonMissing},
+		{Path: "Status", Reason: ReasonDefault},
+		{Path: "Secret", Reason: ReasonMasked},
+		{Path: "Address", Reason: ReasonNested},
+		{Path: "Address.City", Reason: ReasonSet},
+	}, events)
+}
@@ -0,0 +1,145 @@
+package use
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Tracker accumulates changes of one destination over more copy calls.
+// It is not safe for concurrent use.
+type Tracker struct {
+	m        *Mapper
+	dest     any
+	paths    []string                 // in order of the first change
+	orig     map[string]reflect.Value // path -> value before the first change
+	defaults map[string]bool          // paths set to the default value by the last change
+}
+
+// Change is a net change of a single field.
+type Change struct {
+	Path    string // path of the field in the same format as setFields
+	Old     any    // value before the first change (since Track or Reset)
+	New     any    // current value
+	Default bool   // the current value is the default from the tag (`default=` option)
+}
+
+// Track returns a Tracker of dest (reference to struct). All changes made by From, In
+// and Auto of the Tracker are recorded and Dirty reports which fields differ from their
+// original values.
+//
+// Example:
+//
+//	tr := use.Track(&entity)
+//	_, err := tr.From(&input)
+//	_, err = tr.In(&defaults)
+//	for _, ch := range tr.Dirty() {
+//		// update ch.Path column
+//	}
+//	tr.Reset() // after save
+func Track(dest any) *Tracker {
+	return defaultMapper.Track(dest)
+}
+
+// Track is like the package level Track, using the mapper configuration.
+func (m *Mapper) Track(dest any) *Tracker {
+	return &Tracker{m: m, dest: dest, orig: map[string]reflect.Value{}, defaults: map[string]bool{}}
+}
+
+// From copies src to the tracked destination as From(dest, src, opts...) and records the changes.
+func (t *Tracker) From(src any, opts ...Option) (setFields []string, err error) {
+	return t.apply(fromMode, src, opts)
+}
+
+// In copies src to the tracked destination as In(dest, src, opts...) and records the changes.
+func (t *Tracker) In(src any, opts ...Option) (setFields []string, err error) {
+	return t.apply(inMode, src, opts)
+}
+
+// Auto copies src to the tracked destination as Auto(dest, src, opts...) and records the changes.
+func (t *Tracker) Auto(src any, opts ...Option) (setFields []string, err error) {
+	return t.apply(autoMode, src, opts)
+}
+
+func (t *Tracker) apply(md mode, src any, opts []Option) ([]string, error) {
+	r := t.m.newRun(md, opts)
+	r.journal = true
+
+	setFields, err := r.apply(t.dest, src)
+
+	// partial changes are recorded too, the destination is modified
+	for _, e := range r.undo {
+		if e.nested {
+			continue
+		}
+		if _, ok := t.orig[e.path]; ok {
+			continue
+		}
+		t.orig[e.path] = e.old
+		t.paths = append(t.paths, e.path)
+	}
+	for _, path := range r.setFields {
+		delete(t.defaults, path)
+	}
+	for _, path := range r.defaults {
+		t.defaults[path] = true
+	}
+
+	return setFields, err
+}
+
+// Dirty returns the fields whose current value differs from the original one
+// (the values are compared deeply, pointers by the values they point to).
+// A field changed and then changed back is not listed.
+func (t *Tracker) Dirty() []Change {
+	var changes []Change
+	root := reflect.ValueOf(t.dest)
+	for _, path := range t.paths {
+		old := t.orig[path].Interface()
+
+		var cur any
+		if fv, ok := fieldByPath(root, path); ok {
+			cur = fv.Interface()
+		}
+
+		if reflect.DeepEqual(old, cur) {
+			continue
+		}
+		changes = append(changes, Change{Path: path, Old: old, New: cur, Default: t.defaults[path]})
+	}
+	return changes
+}
+
+// Reset forgets all recorded changes, the current values become the original ones.
+func (t *Tracker) Reset() {
+	t.paths = nil
+	t.orig = map[string]reflect.Value{}
+	t.defaults = map[string]bool{}
+}
+
+// fieldByPath returns the field on dotted path (with array indexes like "Addresses[1].City").
+// It is not found when some of the structs on the path is nil.
+func fieldByPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, seg := range strings.Split(path, ".") {
+		name, idx, hasIdx := splitIndex(seg)
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+		if hasIdx {
+			if v.Kind() != reflect.Array || idx >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(idx)
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,65 @@
+package use
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrack(t *testing.T) {
+	type Address struct {
+		City string `usefrom:"" usein:""`
+	}
+
+	type T struct {
+		Name    string   `usefrom:""`
+		Email   *string  `usefrom:""`
+		Age     int      `usefrom:",omitmissing"`
+		Status  string   `usefrom:",omitmissing"`
+		Address *Address `usefrom:""`
+	}
+
+	type Input struct {
+		Name    *string
+		Email   *string
+		Age     *int
+		Address *Address
+	}
+
+	type Defaults struct {
+		Status string `usein:""`
+		Name   string `usein:""`
+	}
+
+	obj := T{Name: "John", Email: asRef("john@example.com"), Age: 30, Status: "active"}
+	tr := Track(&obj)
+
+	_, err := tr.From(&Input{
+		Name:    asRef("Jane"),
+		Email:   asRef("john@example.com"), // set, but the same value
+		Age:     asRef(31),
+		Address: &Address{City: "Prague"},
+	})
+	require.NoError(t, err)
+
+	// changed back to original and status changed
+	_, err = tr.In(&Defaults{Status: "inactive", Name: "John"})
+	require.NoError(t, err)
+
+	// age changed again, the original is still the first one
+	_, err = tr.From(&Input{Age: asRef(32)})
+	require.NoError(t, err)
+
+	require.Equal(t, []Change{
+		{Path: "Age", Old: 30, New: 32},
+		{Path: "Address.City", Old: "", New: "Prague"},
+		{Path: "Status", Old: "active", New: "inactive"},
+	}, tr.Dirty())
+
+	tr.Reset()
+	require.Empty(t, tr.Dirty())
+
+	_, err = tr.From(&Input{Age: asRef(33)})
+	require.NoError(t, err)
+	require.Equal(t, []Change{{Path: "Age", Old: 32, New: 33}}, tr.Dirty())
+}
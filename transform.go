@@ -0,0 +1,147 @@
+package use
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type transformKey struct {
+	name string
+	t    reflect.Type
+}
+
+// transforms is a registry of named functions modifying the copied values.
+type transforms struct {
+	mu  sync.RWMutex
+	fns map[transformKey]reflect.Value
+}
+
+// builtinTransform works on all types of its kinds.
+type builtinTransform struct {
+	kinds []reflect.Kind
+	fn    func(v reflect.Value) // modifies addressable v
+}
+
+var builtinTransforms = map[string]builtinTransform{
+	"trim": {
+		kinds: []reflect.Kind{reflect.String},
+		fn:    func(v reflect.Value) { v.SetString(strings.TrimSpace(v.String())) },
+	},
+	"lower": {
+		kinds: []reflect.Kind{reflect.String},
+		fn:    func(v reflect.Value) { v.SetString(strings.ToLower(v.String())) },
+	},
+	"upper": {
+		kinds: []reflect.Kind{reflect.String},
+		fn:    func(v reflect.Value) { v.SetString(strings.ToUpper(v.String())) },
+	},
+	"abs": {
+		kinds: []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64},
+		fn: func(v reflect.Value) {
+			switch v.Kind() {
+			case reflect.Float32, reflect.Float64:
+				v.SetFloat(math.Abs(v.Float()))
+			default:
+				if v.Int() < 0 && !v.OverflowInt(-v.Int()) {
+					v.SetInt(-v.Int())
+				}
+			}
+		},
+	},
+}
+
+func (ts *transforms) register(name string, fn any) error {
+	if name == "" || strings.ContainsAny(name, ",|=") {
+		return fmt.Errorf("invalid transform name %q", name)
+	}
+
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return errors.New("transform must be a function")
+	}
+
+	ft := fv.Type()
+	if ft.NumIn() != 1 || ft.NumOut() < 1 || ft.NumOut() > 2 || ft.IsVariadic() || ft.Out(0) != ft.In(0) {
+		return fmt.Errorf("transform %s must be func(T) T or func(T) (T, error)", ft)
+	}
+	if ft.NumOut() == 2 && ft.Out(1) != errorType {
+		return fmt.Errorf("transform %s must be func(T) T or func(T) (T, error)", ft)
+	}
+	if ft.In(0).Kind() == reflect.Ptr {
+		return fmt.Errorf("transform %s must not use pointer type", ft)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.fns == nil {
+		ts.fns = make(map[transformKey]reflect.Value)
+	}
+	ts.fns[transformKey{name: name, t: ft.In(0)}] = fv
+	return nil
+}
+
+// has reports if the transform is defined for type t (registered or built-in).
+func (ts *transforms) has(name string, t reflect.Type) bool {
+	if _, ok := ts.lookup(name, t); ok {
+		return true
+	}
+	for _, k := range builtinTransforms[name].kinds {
+		if t.Kind() == k {
+			return true
+		}
+	}
+	return false
+}
+
+func (ts *transforms) lookup(name string, t reflect.Type) (reflect.Value, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	fv, ok := ts.fns[transformKey{name: name, t: t}]
+	return fv, ok
+}
+
+// apply runs the transforms in order on the copy of non nil v (dereferenced).
+// The returned value is addressable.
+func (ts *transforms) apply(names []string, v reflect.Value) (reflect.Value, error) {
+	bv, _ := baseValue(v)
+	cv := reflect.New(bv.Type()).Elem()
+	cv.Set(bv)
+
+	for _, name := range names {
+		if !ts.has(name, cv.Type()) {
+			return reflect.Value{}, fmt.Errorf("transform %q is not defined for type %s", name, cv.Type())
+		}
+
+		fn, ok := ts.lookup(name, cv.Type())
+		if !ok {
+			builtinTransforms[name].fn(cv)
+			continue
+		}
+
+		out := fn.Call([]reflect.Value{cv})
+		if len(out) == 2 && !out[1].IsNil() {
+			return reflect.Value{}, fmt.Errorf("transform %q: %w", name, out[1].Interface().(error))
+		}
+		cv.Set(out[0])
+	}
+	return cv, nil
+}
+
+// checkTransforms verifies the transforms of the tag are defined for the destination field.
+// Optional destinations are checked when the value is set.
+func (m *Mapper) checkTransforms(tg *tag, destField reflect.StructField) error {
+	if isOptional(destField.Type) {
+		return nil
+	}
+	t := baseType(destField.Type)
+	for _, name := range tg.transforms {
+		if !m.tr.has(name, t) {
+			return fmt.Errorf("transform %q of field %q is not defined for type %s", name, destField.Name, t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package use
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	m := NewMapper(Config{})
+	require.NoError(t, m.RegisterTransform("percent", func(n int) int {
+		if n > 100 {
+			return 100
+		}
+		return n
+	}))
+	require.NoError(t, m.RegisterTransform("phone", func(s string) (string, error) {
+		s = strings.NewReplacer(" ", "", "-", "").Replace(s)
+		if !strings.HasPrefix(s, "+") {
+			return "", errors.New("missing country code")
+		}
+		return s, nil
+	}))
+
+	type Entity struct {
+		Email    *string        `usefrom:",transform=trim|lower"`
+		Name     *string        `usefrom:",transform=trim,nooverwrite"`
+		Discount int            `usefrom:",transform=abs|percent"`
+		Phone    string         `usefrom:",transform=phone"`
+		Note     sql.NullString `usefrom:",transform=upper"`
+	}
+
+	type Input struct {
+		Email    *string
+		Name     *string
+		Discount int
+		Phone    string
+		Note     *string
+	}
+
+	email := "  John@Example.COM "
+	src := Input{
+		Email:    &email,
+		Name:     asRef("  new name "),
+		Discount: -150,
+		Phone:    "+420 123-456-789",
+		Note:     asRef("note"),
+	}
+
+	dest := Entity{}
+	_, err := m.From(&dest, &src)
+	require.NoError(t, err)
+	require.Equal(t, Entity{
+		Email:    asRef("john@example.com"),
+		Name:     asRef("new name"),
+		Discount: 100,
+		Phone:    "+420123456789",
+		Note:     sql.NullString{String: "NOTE", Valid: true},
+	}, dest)
+	require.Equal(t, "  John@Example.COM ", email, "source is not modified")
+
+	t.Run("tracked final value", func(t *testing.T) {
+		dest := Entity{Name: asRef("old")}
+		tr := m.Track(&dest)
+		_, err := tr.From(&Input{Name: asRef(" x "), Email: asRef("A@B.C "), Phone: "+1"})
+		require.NoError(t, err)
+		require.Contains(t, tr.Dirty(), Change{Path: "Email", Old: (*string)(nil), New: asRef("a@b.c")})
+		require.Equal(t, asRef("old"), dest.Name)
+	})
+
+	t.Run("transform error", func(t *testing.T) {
+		dest := Entity{}
+		_, err := m.From(&dest, &Input{Phone: "123"})
+		require.ErrorContains(t, err, `"Phone"`)
+		require.ErrorContains(t, err, "missing country code")
+	})
+
+	t.Run("unknown transform", func(t *testing.T) {
+		type Dest struct {
+			Discount int `usefrom:",transform=lower"`
+		}
+		_, err := m.From(&Dest{}, &Input{})
+		require.ErrorContains(t, err, `transform "lower" of field "Discount" is not defined for type int`)
+
+		// registered only on m
+		_, err = From(&Entity{}, &src)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid registration", func(t *testing.T) {
+		require.Error(t, m.RegisterTransform("", strings.TrimSpace))
+		require.Error(t, m.RegisterTransform("a|b", strings.TrimSpace))
+		require.Error(t, m.RegisterTransform("conv", func(s string) int { return len(s) }))
+		require.Error(t, m.RegisterTransform("ptr", func(s *string) *string { return s }))
+	})
+}
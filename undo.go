@@ -0,0 +1,70 @@
+package use
+
+import "reflect"
+
+// Undo restores the destination to the state before the copy call which returned it.
+type Undo func()
+
+// WithUndo stores into u the function reverting all changes the call made to the destination
+// (including nested structs allocated during the copy, their references are restored to nil).
+// It is set even when the call fails, so the partial changes can be reverted too.
+//
+// Example:
+//
+//	var undo use.Undo
+//	_, err := use.From(&entity, &input, use.WithUndo(&undo))
+//	...
+//	if err := db.Save(&entity); err != nil {
+//		undo() // entity is the same as before From
+//	}
+func WithUndo(u *Undo) Option {
+	return func(o *options) {
+		o.undo = u
+	}
+}
+
+type undoEntry struct {
+	path   string
+	field  reflect.Value
+	old    reflect.Value
+	nested bool // allocation of nested struct
+}
+
+// snapshot returns a copy of the field value, when undo is requested (or changes are tracked).
+func (r *run) snapshot(fv reflect.Value) reflect.Value {
+	if (r.opts.undo == nil && !r.journal) || !fv.IsValid() {
+		return reflect.Value{}
+	}
+	old := reflect.New(fv.Type()).Elem()
+	old.Set(fv)
+	return old
+}
+
+// changed records the change of the field with the value from snapshot.
+func (r *run) changed(path string, fv, old reflect.Value, nested bool) {
+	if old.IsValid() {
+		r.undo = append(r.undo, undoEntry{path: path, field: fv, old: old, nested: nested})
+	}
+}
+
+// setUndo hands the recorded changes to the caller.
+func (r *run) setUndo() {
+	if r.opts.undo == nil {
+		return
+	}
+
+	entries := r.undo
+	*r.opts.undo = func() {
+		for i := len(entries) - 1; i >= 0; i-- {
+			entries[i].field.Set(entries[i].old)
+		}
+	}
+}
+
+// revert restores the recorded changes, nothing is reported as set.
+func (r *run) revert() {
+	for i := len(r.undo) - 1; i >= 0; i-- {
+		r.undo[i].field.Set(r.undo[i].old)
+	}
+	r.undo, r.setFields, r.defaults = nil, nil, nil
+}
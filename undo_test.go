@@ -0,0 +1,88 @@
+package use
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndo(t *testing.T) {
+	type Nested struct {
+		NestedF1 string `usefrom:"" usein:""`
+	}
+
+	type T struct {
+		F1 string            `usefrom:"" usein:""`
+		F2 *int              `usefrom:"" usein:""`
+		F3 []int             `usefrom:"" usein:""`
+		F4 map[string]string `usefrom:"" usein:""`
+		F5 *Nested           `usefrom:"" usein:""`
+		F6 *Nested           `usefrom:"" usein:""`
+		F7 Nested            `usefrom:"" usein:""`
+	}
+
+	newObj := func() T {
+		return T{
+			F1: "old f1",
+			F2: nil,
+			F3: []int{1, 2},
+			F4: map[string]string{"a": "b"},
+			F5: nil,
+			F6: &Nested{NestedF1: "old f6"},
+			F7: Nested{NestedF1: "old f7"},
+		}
+	}
+
+	src := T{
+		F1: "new f1",
+		F2: asRef(42),
+		F3: []int{3},
+		F4: map[string]string{"c": "d"},
+		F5: &Nested{NestedF1: "new f5"},
+		F6: &Nested{NestedF1: "new f6"},
+		F7: Nested{NestedF1: "new f7"},
+	}
+
+	for name, copyFn := range map[string]func(dest, src any, opts ...Option) ([]string, error){
+		"From": From,
+		"In":   In,
+		"Auto": Auto,
+	} {
+		t.Run(name, func(t *testing.T) {
+			obj := newObj()
+			f6 := obj.F6
+
+			var undo Undo
+			_, err := copyFn(&obj, &src, WithUndo(&undo))
+			require.NoError(t, err)
+			require.NotEqual(t, newObj(), obj)
+
+			undo()
+			require.Equal(t, newObj(), obj)
+			require.Nil(t, obj.F5)
+			require.Same(t, f6, obj.F6)
+		})
+	}
+}
+
+func TestUndoAfterError(t *testing.T) {
+	type T struct {
+		F1 string `usefrom:""`
+		F2 int    `usefrom:""`
+	}
+
+	type TInput struct {
+		F1 string
+		F2 string // wrong type
+	}
+
+	obj := T{F1: "old f1", F2: 1}
+
+	var undo Undo
+	_, err := From(&obj, &TInput{F1: "new f1", F2: "2"}, WithUndo(&undo))
+	require.Error(t, err)
+	require.Equal(t, "new f1", obj.F1) // set before the error
+
+	undo()
+	require.Equal(t, T{F1: "old f1", F2: 1}, obj)
+}
@@ -14,13 +14,30 @@
 //   - use.In to copy values from source to destination,
 //     where the copy rules are defined in the source struct tags
 //
+// use.Reverse applies the `usefrom` tags of the source struct backwards.
+//
+// When the structs are mostly 1:1, use.Auto copies all fields with matching
+// names and the tags are needed only for exceptions (renames, options, exclusion).
+//
+// use.DecodeJSON, use.FromForm, use.FromEnv and use.FromFlags apply keyed sources (JSON object,
+// form values, environment variables, set flags) without a source struct, the keys are the source
+// names of `usefrom` tags. They work like From: an absent or null value is a nil source field,
+// the nested structs are allocated only when some of their keys are present, and the tag options,
+// converters and options like Mask, WithUndo or OnlyChanged apply the same way. The string values
+// of forms, environment and flags are parsed into the field types (strconv, time.Duration,
+// time.Time, encoding.TextUnmarshaler, inner types of optionals), an empty value of non-string
+// field is nil. Nothing is copied when some values are invalid (*ParseError, the first decoding
+// error of JSON) or required fields are missing (*RequiredError).
+//
+// Different conventions (tag names, default tag options, converters)
+// can be used with own Mapper created by NewMapper.
+//
 // For examples of usage, see use_test.go.
 //
 // # To do:
 //
 //   - support for interfaces
 //   - source as map[string]any
-//   - LOW type cache for faster processing
 //   - WONTFIX support for maps and slices with nested structs (and transforming them)
 //     (conversion of map to input struct should happen somewhere else)
 package use
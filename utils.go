@@ -2,9 +2,22 @@ package use
 
 import "reflect"
 
+// isNil reports if v has no value. Optional values (like sql.NullString) without value are nil too.
 func isNil(v reflect.Value) bool {
 	switch v.Kind() {
-	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr:
+		if !v.IsNil() && isOptional(v.Type()) {
+			return !optionalPresent(v.Elem())
+		}
+		return v.IsNil()
+	case reflect.Struct:
+		if isOptional(v.Type()) {
+			return !optionalPresent(v)
+		}
+		return false
+	case reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
 		return v.IsNil()
 	case reflect.Interface:
 		return v.IsZero() || v.IsNil()
@@ -20,6 +33,14 @@ func derefValue(vv reflect.Value) (indirect bool, v reflect.Value) {
 	return false, vv
 }
 
+// refAny returns the reference to the struct in v (struct or pointer to struct).
+func refAny(v reflect.Value) any {
+	if v.Kind() == reflect.Ptr {
+		return v.Interface()
+	}
+	return v.Addr().Interface()
+}
+
 func derefType(vt reflect.Type) (indirect bool, t reflect.Type) {
 	if vt.Kind() == reflect.Ptr {
 		return true, vt.Elem()
@@ -27,33 +48,45 @@ func derefType(vt reflect.Type) (indirect bool, t reflect.Type) {
 	return false, vt
 }
 
-func typesMatch(outFt, inpFt reflect.Type) bool {
-	if outFt == inpFt {
-		return true
+// baseType returns t without all its pointer levels (**T -> T).
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
+	return t
+}
 
-	if outFt.Kind() == reflect.Ptr && outFt.Elem() == inpFt {
-		return true
+// baseValue returns v without all its pointer levels. ok is false when some of the pointers is nil.
+func baseValue(v reflect.Value) (bv reflect.Value, ok bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
 	}
+	return v, true
+}
 
-	if inpFt.Kind() == reflect.Ptr && inpFt.Elem() == outFt {
-		return true
+// isNullRef reports if v is a multi-level pointer with non nil outer and nil inner
+// pointer (e.g. **string encoding "present but null").
+func isNullRef(v reflect.Value) bool {
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
 	}
+	_, ok := baseValue(v)
+	return !ok
+}
 
-	return false
+// typesMatch reports if the types are the same, regardless of pointer levels on both sides.
+func typesMatch(outFt, inpFt reflect.Type) bool {
+	return baseType(outFt) == baseType(inpFt)
 }
 
-// containsStructOrPtrToStruct does not suport double (or more) references (e.g. **SomeStruct). It is often a mistake to use them.
+// containsStructOrPtrToStruct reports if t is a struct or reference (of any depth) to struct.
+// Optional values (like sql.NullString) are not considered structs.
 func containsStructOrPtrToStruct(t reflect.Type) bool {
-	if t.Kind() == reflect.Struct {
-		return true
-	}
-
-	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
-		return true
-	}
-
-	return false
+	t = baseType(t)
+	return t.Kind() == reflect.Struct && !isOptional(t)
 }
 
 func asRef[T any](s T) *T {